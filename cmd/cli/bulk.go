@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/sitefile"
+)
+
+// Formato pela flag -as ou pela extensão do arquivo
+func fileFormat(name, path string) (sitefile.Format, error) {
+	if name != "" {
+		return sitefile.ParseFormat(name)
+	}
+	return sitefile.FormatForFile(path)
+}
+
+// import [ARQUIVO]: cadastrar no servidor os sites de um arquivo json, csv ou txt
+// (padrão: o sites.txt usado pelo monitoramento local). "-" lê da entrada padrão
+func remoteImport(args []string) int {
+	var opts remoteOptions
+	var as, onConflict string
+	var dryRun bool
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.StringVar(&as, "as", "", "formato do arquivo: json, csv ou txt (padrão: pela extensão)")
+	fs.StringVar(&onConflict, "on-conflict", "skip", "URLs já cadastradas: skip, update ou fail")
+	fs.BoolVar(&dryRun, "dry-run", false, "apenas validar, sem salvar")
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	path := sitesFile
+	if fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Informe apenas um arquivo")
+		return exitUnknown
+	}
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	format, err := fileFormat(as, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err, "(use -as)")
+		return exitUnknown
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ocorreu um erro ao abrir o arquivo:", err)
+			return exitUnknown
+		}
+		defer file.Close()
+		input = file
+	}
+
+	result, err := api.ImportSites(input, string(format), onConflict, dryRun)
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		printJSON(result)
+	} else {
+		printImportResult(result)
+	}
+
+	// Falhas parciais não impedem as demais linhas, mas são sinalizadas no código de saída
+	if result.Failed > 0 {
+		return exitWarning
+	}
+	return exitOK
+}
+
+func printImportResult(result *models.ImportResult) {
+	if result.Failed > 0 {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINHA\tURL\tERRO")
+		for _, row := range result.Rows {
+			if row.Status == models.ImportFailed {
+				fmt.Fprintf(tw, "%d\t%s\t%s\n", row.Line, row.URL, row.Error)
+			}
+		}
+		tw.Flush()
+		fmt.Println("")
+	}
+
+	if result.DryRun {
+		fmt.Println("Simulação: nenhum site foi salvo")
+	}
+	fmt.Printf("%d linhas: %d criados, %d atualizados, %d já cadastrados, %d com erro\n",
+		result.Total, result.Created, result.Updated, result.Skipped, result.Failed)
+}
+
+// export [-o ARQUIVO]: salvar os sites do servidor em json, csv ou txt
+func remoteExport(args []string) int {
+	var opts remoteOptions
+	var as, output string
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.StringVar(&as, "as", "", "formato do arquivo: json, csv ou txt (padrão: pela extensão de -o, ou json)")
+	fs.StringVar(&output, "o", "", "arquivo de saída (padrão: saída padrão)")
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	format := sitefile.FormatJSON
+	if as != "" || output != "" {
+		var err error
+		if format, err = fileFormat(as, output); err != nil {
+			fmt.Fprintln(os.Stderr, err, "(use -as)")
+			return exitUnknown
+		}
+	}
+
+	if output == "" {
+		if err := api.ExportSites(os.Stdout, string(format)); err != nil {
+			return reportError(err)
+		}
+		return exitOK
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ocorreu um erro ao criar o arquivo:", err)
+		return exitUnknown
+	}
+	defer file.Close()
+
+	if err := api.ExportSites(file, string(format)); err != nil {
+		return reportError(err)
+	}
+	fmt.Fprintln(os.Stderr, "Sites exportados para", output)
+	return exitOK
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/checker"
+)
+
+const defaultWarnLatency = 2 * time.Second
+
+// Códigos de saída no padrão Nagios
+const (
+	exitOK       = 0
+	exitWarning  = 1
+	exitCritical = 2
+	exitUnknown  = 3
+)
+
+var siteChecker = checker.New()
+
+// Executar subcomando não interativo e retornar o código de saída
+func runCommand(name string, args []string) int {
+	switch name {
+	case "check":
+		return runCheck(args)
+	case "remote":
+		return runRemote(args)
+	case "logs":
+		return runLogs(args)
+	case "help", "-h", "--help":
+		printUsage(os.Stdout)
+		return exitOK
+	default:
+		fmt.Fprintln(os.Stderr, "Não conheço este comando:", name)
+		printUsage(os.Stderr)
+		return exitUnknown
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: monitor-cli [comando] [opções]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Sem comando, abre o menu interativo.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Comandos:")
+	fmt.Fprintln(w, "  check   Verificar os sites uma vez e sair com código Nagios (0 ok, 1 degradado, 2 fora do ar, 3 desconhecido)")
+	fmt.Fprintln(w, "  logs    Consultar o arquivo de log local (-site, -since, -from, -to, -status, -summary)")
+	fmt.Fprintln(w, "  remote  Gerenciar o servidor de monitoramento pela API (monitor-cli remote help)")
+}
+
+// check: verificação única para pipelines de CI
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	file := fs.String("file", sitesFile, "arquivo com a lista de sites (usado quando nenhuma URL é informada)")
+	format := fs.String("format", "table", "formato do relatório: table, json ou junit")
+	output := fs.String("output", "", "arquivo de saída do relatório (padrão: stdout)")
+	timeout := fs.Duration("timeout", checker.DefaultTimeout, "tempo máximo de cada verificação")
+	warnLatency := fs.Duration("warn-latency", defaultWarnLatency, "tempo de resposta acima do qual o site é considerado degradado")
+	expect := fs.String("expect", checker.DefaultExpectedStatus, "status codes HTTP aceitos (ex.: 200-299,301)")
+	keyword := fs.String("keyword", "", "texto que deve aparecer no corpo da resposta HTTP")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUnknown
+	}
+
+	sites := fs.Args()
+	// O parse para na primeira URL: uma flag depois dela seria tratada como site
+	for _, site := range sites {
+		if strings.HasPrefix(site, "-") {
+			fmt.Fprintf(os.Stderr, "Argumento inesperado: %s (as flags vêm antes das URLs)\n", site)
+			return exitUnknown
+		}
+	}
+	if len(sites) == 0 {
+		var err error
+		sites, err = readSitesFromFile(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ocorreu um erro ao abrir o arquivo:", err)
+			return exitUnknown
+		}
+	}
+
+	if len(sites) == 0 {
+		fmt.Fprintln(os.Stderr, "Nenhum site para verificar")
+		return exitUnknown
+	}
+
+	reporter, ok := reporters[*format]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Formato desconhecido:", *format)
+		return exitUnknown
+	}
+
+	results := make([]checker.Result, 0, len(sites))
+	for _, site := range sites {
+		cfg := checker.Config{
+			Target:         site,
+			Timeout:        *timeout,
+			ExpectedStatus: *expect,
+			Keyword:        *keyword,
+			DegradedAfter:  *warnLatency,
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, "Configuração inválida:", err)
+			return exitUnknown
+		}
+		results = append(results, checkSite(cfg))
+	}
+
+	if err := writeReport(*output, reporter, results); err != nil {
+		fmt.Fprintln(os.Stderr, "Ocorreu um erro ao escrever o relatório:", err)
+		return exitUnknown
+	}
+
+	return exitCode(results)
+}
+
+// Escrever o relatório no stdout ou no arquivo de saída. O erro do Close conta: em
+// disco cheio ou NFS é nele que a escrita falha
+func writeReport(output string, write reporter, results []checker.Result) error {
+	if output == "" {
+		return write(os.Stdout, results)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Verificar um site com o verificador compartilhado com o servidor
+func checkSite(cfg checker.Config) checker.Result {
+	return siteChecker.Check(context.Background(), cfg)
+}
+
+// Pior estado encontrado define o código de saída
+func exitCode(results []checker.Result) int {
+	code := exitOK
+	for _, result := range results {
+		switch result.Status {
+		case checker.StatusDown:
+			return exitCritical
+		case checker.StatusDegraded:
+			code = exitWarning
+		}
+	}
+	return code
+}
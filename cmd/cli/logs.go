@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/logfile"
+)
+
+// Formatos aceitos em -from e -to
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+// logs: consultar o arquivo de log local
+func runLogs(args []string) int {
+	var filter logfile.Filter
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	file := fs.String("file", logFile, "arquivo de log")
+	fs.StringVar(&filter.Site, "site", "", "filtrar pela URL (ou parte dela)")
+	since := fs.Duration("since", 0, "apenas registros recentes (ex.: 24h)")
+	from := fs.String("from", "", "data inicial (AAAA-MM-DD, AAAA-MM-DD HH:MM ou RFC3339)")
+	to := fs.String("to", "", "data final, exclusiva (mesmos formatos de -from)")
+	fs.StringVar(&filter.Status, "status", "", "online, offline, up, degraded ou down")
+	summary := fs.Bool("summary", false, "exibir resumo de uptime por site")
+	format := fs.String("format", "table", "formato da saída: table ou json")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUnknown
+	}
+
+	if *format != "table" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "Formato desconhecido:", *format)
+		return exitUnknown
+	}
+
+	switch filter.Status {
+	case "", "all", "online", "offline", "up", "degraded", "down":
+	default:
+		fmt.Fprintln(os.Stderr, "Status desconhecido:", filter.Status)
+		return exitUnknown
+	}
+
+	var err error
+	if filter.Since, err = parseDate(*from); err != nil {
+		fmt.Fprintln(os.Stderr, "Data inicial inválida:", *from)
+		return exitUnknown
+	}
+	if filter.Until, err = parseDate(*to); err != nil {
+		fmt.Fprintln(os.Stderr, "Data final inválida:", *to)
+		return exitUnknown
+	}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	var records []logfile.Record
+	err = logfile.Read(*file, filter, func(record logfile.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ocorreu um erro ao ler o arquivo de log:", err)
+		return exitUnknown
+	}
+
+	var output interface{} = records
+	if *summary {
+		output = logfile.Summarize(records)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return exitUnknown
+		}
+		return exitOK
+	}
+
+	if *summary {
+		writeSummary(os.Stdout, logfile.Summarize(records))
+	} else {
+		writeRecords(os.Stdout, records)
+	}
+	return exitOK
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	var err error
+	for _, layout := range dateLayouts {
+		var parsed time.Time
+		if parsed, err = time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func writeRecords(w io.Writer, records []logfile.Record) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATA\tSITE\tSTATUS\tCÓDIGO\tTEMPO\tERRO")
+	for _, record := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%dms\t%s\n",
+			record.Time.Local().Format("02/01/2006 15:04:05"), record.Site, record.Status,
+			record.StatusCode, record.ResponseTime, record.Error)
+	}
+	tw.Flush()
+}
+
+func writeSummary(w io.Writer, summaries []logfile.SiteSummary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SITE\tVERIFICAÇÕES\tONLINE\tOFFLINE\tDEGRADADO\tUPTIME\tTEMPO MÉDIO\tÚLTIMA VERIFICAÇÃO")
+	for _, summary := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.2f%%\t%dms\t%s\n",
+			summary.Site, summary.Checks, summary.Online, summary.Offline, summary.Degraded,
+			summary.Uptime, summary.AvgResponseTime, summary.LastCheck.Local().Format("02/01/2006 15:04:05"))
+	}
+	tw.Flush()
+}
@@ -4,17 +4,31 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/luacarol/website-monitoring/internal/checker"
+	"github.com/luacarol/website-monitoring/internal/logfile"
 )
 
 const monitorings = 3
 const delay = 5
+const sitesFile = "sites.txt"
+
+// Arquivo de log JSONL, rotacionado ao atingir maxLogSize
+const logFile = "log.jsonl"
+const maxLogSize = 10 * 1024 * 1024
+const maxLogBackups = 5
+
+var logWriter = logfile.NewWriter(logFile, maxLogSize, maxLogBackups)
 
 func main() {
+	// Subcomandos não interativos (ex.: "check" para pipelines de CI)
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	showIntroduction()
 
 	for {
@@ -88,7 +102,11 @@ func initMonitoring() {
 
 	// var sites = []string{"https://www.alura.com.br/", "https://httpbin.org/status/404", "https://www.caelum.com.br/", "https://httpbin.org/status/500"}
 
-	sites := readSitesFromFile()
+	sites, err := readSitesFromFile(sitesFile)
+	if err != nil {
+		fmt.Println("Ocorreu um erro ao abrir o arquivo:", err)
+		return
+	}
 
 	// for i, site := range sites {
 	// 	fmt.Println("Estou passando na posição ", i, "do meu slice e essa posição tem o site:", site)
@@ -107,35 +125,38 @@ func initMonitoring() {
 	fmt.Println("")
 }
 
-func verifySite(site string) {
-	response, err := http.Get(site)
-	if err != nil {
-		fmt.Println("Erro ao acessar o site:", err)
-		return
-	}
-	defer response.Body.Close()
-
-	// fmt.Println("Response:", response)
+func verifySite(site string) checker.Result {
+	result := checkSite(checker.Config{
+		Target:        site,
+		DegradedAfter: defaultWarnLatency,
+	})
 
-	if response.StatusCode == 200 {
+	switch {
+	case result.Status == checker.StatusUp:
 		fmt.Println("Site:", site, "foi carregado com sucesso!")
-		registerLog(site, true)
-	} else {
-		fmt.Println("Site:", site, "está com problemas. Status Code:", response.StatusCode)
-		registerLog(site, false)
+	case result.Status == checker.StatusDegraded:
+		fmt.Println("Site:", site, "carregou, mas está lento:", result.ResponseTime)
+	case result.StatusCode != 0:
+		fmt.Println("Site:", site, "está com problemas. Status Code:", result.StatusCode)
+	default:
+		fmt.Println("Erro ao acessar o site:", result.Error)
 	}
+
+	registerLog(result)
+	return result
 }
 
-func readSitesFromFile() []string {
+func readSitesFromFile(path string) ([]string, error) {
 	var sites []string
 
-	file, err := os.Open("sites.txt")
+	file, err := os.Open(path)
 	// file, err := os.ReadFile("sites.txt")
 	// fmt.Println(string(file))
 
 	if err != nil {
-		fmt.Println("Ocorreu um erro ao abrir o arquivo:", err)
+		return nil, err
 	}
+	defer file.Close()
 
 	reader := bufio.NewReader(file)
 	for {
@@ -143,38 +164,51 @@ func readSitesFromFile() []string {
 		line = strings.TrimSpace(line)
 		// fmt.Println(line)
 
-		sites = append(sites, line)
+		// Ignorar linhas em branco e comentários
+		if line != "" && !strings.HasPrefix(line, "#") {
+			sites = append(sites, line)
+		}
 
 		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	file.Close()
-	return sites
+	return sites, nil
 }
 
-func registerLog(site string, status bool) {
-	file, err := os.OpenFile("log.txt", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-
-	if err != nil {
-		fmt.Println("Ocorreu um erro ao abrir o arquivo de log:", err)
+func registerLog(result checker.Result) {
+	record := logfile.Record{
+		Time:         result.CheckedAt,
+		Site:         result.Target,
+		Online:       result.IsOnline(),
+		Status:       string(result.Status),
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime.Milliseconds(),
+		Error:        result.Error,
 	}
 
-	file.WriteString(time.Now().Format("02/01/2006 15:04:05") + " - " + site + " - online: " + strconv.FormatBool(status) + "\n")
-
-	// fmt.Println(file)
-	file.Close()
+	if err := logWriter.Append(record); err != nil {
+		fmt.Println("Ocorreu um erro ao escrever o arquivo de log:", err)
+	}
 }
 
 func printLogs() {
 	fmt.Println("Exibindo Logs...")
 
-	file, err := os.ReadFile("log.txt")
+	var records []logfile.Record
+	err := logfile.Read(logFile, logfile.Filter{}, func(record logfile.Record) error {
+		records = append(records, record)
+		return nil
+	})
 
 	if err != nil {
 		fmt.Println("Ocorreu um erro ao ler o arquivo de log:", err)
 	}
 
-	fmt.Println(string(file))
+	writeRecords(os.Stdout, records)
+	fmt.Println("")
 }
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/luacarol/website-monitoring/internal/client"
+	"github.com/luacarol/website-monitoring/internal/models"
+)
+
+const defaultServerURL = "http://localhost:8080"
+
+// Configuração do acesso ao servidor, lida de ~/.config/website-monitor/config.json
+type remoteConfig struct {
+	Server string `json:"server"`
+	APIKey string `json:"api_key"`
+}
+
+// Opções comuns a todos os subcomandos remotos
+type remoteOptions struct {
+	configPath string
+	server     string
+	apiKey     string
+	format     string
+}
+
+func (o *remoteOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.configPath, "config", "", "arquivo de configuração (padrão: ~/.config/website-monitor/config.json)")
+	fs.StringVar(&o.server, "server", "", "URL do servidor (padrão: $MONITOR_SERVER, config ou "+defaultServerURL+")")
+	fs.StringVar(&o.apiKey, "api-key", "", "chave de API (padrão: $MONITOR_API_KEY ou config)")
+	fs.StringVar(&o.format, "format", "table", "formato da saída: table ou json")
+}
+
+// Resolver servidor e chave: flags > variáveis de ambiente > arquivo de configuração
+func (o *remoteOptions) client() (*client.Client, error) {
+	if o.format != "table" && o.format != "json" {
+		return nil, fmt.Errorf("formato desconhecido: %s", o.format)
+	}
+
+	cfg, err := loadRemoteConfig(o.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(o.server, os.Getenv("MONITOR_SERVER"), cfg.Server, defaultServerURL)
+	apiKey := firstNonEmpty(o.apiKey, os.Getenv("MONITOR_API_KEY"), cfg.APIKey)
+
+	return client.New(server, apiKey), nil
+}
+
+func loadRemoteConfig(path string) (remoteConfig, error) {
+	var cfg remoteConfig
+
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("MONITOR_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg, nil
+		}
+		path = filepath.Join(dir, "website-monitor", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// O arquivo padrão é opcional
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("erro ao ler configuração: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("configuração inválida em %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+type remoteCommand func(args []string) int
+
+var remoteCommands = map[string]remoteCommand{
+	"sites":  remoteSites,
+	"add":    remoteAdd,
+	"remove": remoteRemove,
+	"toggle": remoteToggle,
+	"check":  remoteCheck,
+	"logs":   remoteLogs,
+	"stats":  remoteStats,
+	"import": remoteImport,
+	"export": remoteExport,
+}
+
+func printRemoteUsage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: monitor-cli remote <comando> [opções]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Comandos:")
+	fmt.Fprintln(w, "  sites                      Listar sites")
+	fmt.Fprintln(w, "  add -name NOME -url URL    Adicionar site")
+	fmt.Fprintln(w, "  remove ID                  Remover site")
+	fmt.Fprintln(w, "  toggle ID                  Ativar/desativar site")
+	fmt.Fprintln(w, "  check ID                   Verificar site agora")
+	fmt.Fprintln(w, "  logs                       Consultar logs (-site, -status, -start, -end, -code, -min-time, -max-time, -error, -type, -cursor, -page, -limit)")
+	fmt.Fprintln(w, "  stats                      Exibir estatísticas")
+	fmt.Fprintln(w, "  import [ARQUIVO]           Importar sites de json, csv ou txt (padrão: sites.txt; -on-conflict, -dry-run)")
+	fmt.Fprintln(w, "  export [-o ARQUIVO]        Exportar sites em json, csv ou txt (-as)")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Opções comuns: -server, -api-key, -config, -format table|json")
+}
+
+// remote: cliente REST do servidor de monitoramento
+func runRemote(args []string) int {
+	if len(args) == 0 {
+		printRemoteUsage(os.Stderr)
+		return exitUnknown
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printRemoteUsage(os.Stdout)
+		return exitOK
+	}
+
+	command, ok := remoteCommands[args[0]]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Não conheço este comando:", args[0])
+		printRemoteUsage(os.Stderr)
+		return exitUnknown
+	}
+
+	return command(args[1:])
+}
+
+// Interpretar as flags do subcomando e criar o cliente
+func parseRemote(args []string, opts *remoteOptions, fs *flag.FlagSet) (*client.Client, bool) {
+	opts.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, false
+	}
+
+	api, err := opts.client()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	}
+	return api, true
+}
+
+// Ler o ID do site do primeiro argumento posicional
+func parseSiteID(fs *flag.FlagSet) (uint, bool) {
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Informe o ID do site")
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ID inválido:", fs.Arg(0))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func reportError(err error) int {
+	fmt.Fprintln(os.Stderr, "Erro na requisição:", err)
+	return exitCritical
+}
+
+func printJSON(value interface{}) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return reportError(err)
+	}
+	return exitOK
+}
+
+func remoteSites(args []string) int {
+	var opts remoteOptions
+	fs := flag.NewFlagSet("sites", flag.ContinueOnError)
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	response, err := api.ListSites()
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(response)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNOME\tURL\tATIVO\tÚLTIMO STATUS\tÚLTIMA VERIFICAÇÃO\tUPTIME")
+	for _, site := range response.Sites {
+		lastCheck := "-"
+		if !site.LastCheck.IsZero() {
+			lastCheck = site.LastCheck.Local().Format("02/01/2006 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%t\t%d\t%s\t%.2f%%\n",
+			site.ID, site.Name, site.URL, site.Active, site.LastStatus, lastCheck, site.Uptime)
+	}
+	tw.Flush()
+	return exitOK
+}
+
+func remoteAdd(args []string) int {
+	var opts remoteOptions
+	var request models.SiteRequest
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	fs.StringVar(&request.Name, "name", "", "nome do site")
+	fs.StringVar(&request.URL, "url", "", "URL do site")
+	fs.StringVar(&request.CheckType, "type", "", "tipo de verificação: http, tcp ou dns")
+	fs.StringVar(&request.ExpectedStatus, "expect", "", "status codes HTTP aceitos (ex.: 200-299)")
+	fs.StringVar(&request.Keyword, "keyword", "", "texto que deve aparecer no corpo da resposta")
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	if request.Name == "" || request.URL == "" {
+		fmt.Fprintln(os.Stderr, "Informe -name e -url")
+		return exitUnknown
+	}
+
+	response, err := api.CreateSite(request)
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(response)
+	}
+	fmt.Printf("%s (ID %d)\n", response.Message, response.Site.ID)
+	return exitOK
+}
+
+func remoteRemove(args []string) int {
+	var opts remoteOptions
+	fs := flag.NewFlagSet("remove", flag.ContinueOnError)
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+	id, ok := parseSiteID(fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	if err := api.DeleteSite(id); err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(map[string]interface{}{"id": id, "deleted": true})
+	}
+	fmt.Println("Site removido com sucesso")
+	return exitOK
+}
+
+func remoteToggle(args []string) int {
+	var opts remoteOptions
+	fs := flag.NewFlagSet("toggle", flag.ContinueOnError)
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+	id, ok := parseSiteID(fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	response, err := api.ToggleSite(id)
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(response)
+	}
+	fmt.Println(response.Message)
+	return exitOK
+}
+
+func remoteCheck(args []string) int {
+	var opts remoteOptions
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+	id, ok := parseSiteID(fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	response, err := api.CheckSite(id)
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		printJSON(response)
+	} else {
+		result := response.Result
+		fmt.Printf("Site %d: online=%t status=%d tempo=%dms %s\n",
+			result.SiteID, result.IsOnline, result.StatusCode, result.ResponseTime, result.ErrorMessage)
+	}
+
+	// Mesmo código de saída do comando check local
+	if !response.Result.IsOnline {
+		return exitCritical
+	}
+	return exitOK
+}
+
+func remoteLogs(args []string) int {
+	var opts remoteOptions
+	var query models.LogsQuery
+	var siteID uint64
+	var minTime, maxTime int64
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	fs.Uint64Var(&siteID, "site", 0, "ID do site")
+	fs.StringVar(&query.Status, "status", "", "online, offline ou all")
+	fs.StringVar(&query.StartDate, "start", "", "data inicial (AAAA-MM-DD)")
+	fs.StringVar(&query.EndDate, "end", "", "data final (AAAA-MM-DD)")
+	fs.StringVar(&query.StatusCode, "code", "", "status codes (ex.: 5xx, 400-499, 0 para sem resposta)")
+	fs.Int64Var(&minTime, "min-time", -1, "tempo de resposta mínimo (ms)")
+	fs.Int64Var(&maxTime, "max-time", -1, "tempo de resposta máximo (ms)")
+	fs.StringVar(&query.Error, "error", "", "trecho da mensagem de erro")
+	fs.StringVar(&query.CheckType, "type", "", "tipo de verificação: http, tcp ou dns")
+	fs.StringVar(&query.Cursor, "cursor", "", "continuar a partir do cursor da página anterior")
+	fs.IntVar(&query.Page, "page", 1, "página")
+	fs.IntVar(&query.Limit, "limit", 50, "logs por página")
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+	query.SiteID = uint(siteID)
+	if minTime >= 0 {
+		query.MinResponseTime = &minTime
+	}
+	if maxTime >= 0 {
+		query.MaxResponseTime = &maxTime
+	}
+	if query.Cursor != "" {
+		query.Page = 0
+	}
+
+	response, err := api.GetLogs(query)
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(response)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATA\tSITE\tONLINE\tSTATUS\tTEMPO\tERRO")
+	for _, entry := range response.Logs {
+		site := entry.Site.Name
+		if site == "" {
+			site = strconv.FormatUint(uint64(entry.SiteID), 10)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%d\t%dms\t%s\n",
+			entry.CheckedAt.Local().Format("02/01/2006 15:04:05"), site, entry.IsOnline,
+			entry.StatusCode, entry.ResponseTime, entry.ErrorMessage)
+	}
+	tw.Flush()
+	if query.Cursor == "" {
+		fmt.Printf("Página %d de %d (%d logs)\n", response.Page, response.Pages, response.Total)
+	}
+	if response.NextCursor != "" {
+		fmt.Println("Próxima página: -cursor", response.NextCursor)
+	}
+	return exitOK
+}
+
+func remoteStats(args []string) int {
+	var opts remoteOptions
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	api, ok := parseRemote(args, &opts, fs)
+	if !ok {
+		return exitUnknown
+	}
+
+	stats, err := api.GetStats()
+	if err != nil {
+		return reportError(err)
+	}
+
+	if opts.format == "json" {
+		return printJSON(stats)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Sites ativos:\t%d\n", stats.TotalSites)
+	fmt.Fprintf(tw, "Online:\t%d\n", stats.OnlineSites)
+	fmt.Fprintf(tw, "Offline:\t%d\n", stats.OfflineSites)
+	fmt.Fprintf(tw, "Uptime (24h):\t%.2f%%\n", stats.OverallUptime)
+	fmt.Fprintf(tw, "Atualizado em:\t%s\n", stats.LastUpdate.Local().Format("02/01/2006 15:04:05"))
+	tw.Flush()
+	return exitOK
+}
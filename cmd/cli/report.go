@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/checker"
+)
+
+type reporter func(w io.Writer, results []checker.Result) error
+
+var reporters = map[string]reporter{
+	"table": writeTable,
+	"json":  writeJSON,
+	"junit": writeJUnit,
+}
+
+func writeTable(w io.Writer, results []checker.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SITE\tSTATUS\tCÓDIGO\tTEMPO\tERRO")
+
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%dms\t%s\n",
+			result.Target, result.Status, result.StatusCode, result.ResponseTime.Milliseconds(), result.Error)
+	}
+
+	return tw.Flush()
+}
+
+type jsonReport struct {
+	Status    checker.Status `json:"status"`
+	ExitCode  int            `json:"exit_code"`
+	Total     int            `json:"total"`
+	Healthy   int            `json:"healthy"`
+	Degraded  int            `json:"degraded"`
+	Down      int            `json:"down"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Results   []jsonResult   `json:"results"`
+}
+
+// Resultado com o tempo de resposta em millisegundos, como na API do servidor
+type jsonResult struct {
+	checker.Result
+	ResponseTime int64 `json:"response_time"`
+}
+
+func writeJSON(w io.Writer, results []checker.Result) error {
+	report := jsonReport{
+		ExitCode:  exitCode(results),
+		Total:     len(results),
+		CheckedAt: time.Now(),
+	}
+
+	for _, result := range results {
+		switch result.Status {
+		case checker.StatusUp:
+			report.Healthy++
+		case checker.StatusDegraded:
+			report.Degraded++
+		case checker.StatusDown:
+			report.Down++
+		}
+
+		report.Results = append(report.Results, jsonResult{
+			Result:       result,
+			ResponseTime: result.ResponseTime.Milliseconds(),
+		})
+	}
+
+	switch report.ExitCode {
+	case exitOK:
+		report.Status = checker.StatusUp
+	case exitWarning:
+		report.Status = checker.StatusDegraded
+	default:
+		report.Status = checker.StatusDown
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// Estrutura mínima do formato JUnit XML aceita pelos servidores de CI
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, results []checker.Result) error {
+	suite := junitTestSuite{
+		Name:      "website-monitor",
+		Tests:     len(results),
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	var total time.Duration
+	for _, result := range results {
+		total += result.ResponseTime
+
+		testCase := junitTestCase{
+			Name:      result.Target,
+			ClassName: "website-monitor.check",
+			Time:      formatSeconds(result.ResponseTime),
+		}
+
+		switch result.Status {
+		case checker.StatusDown:
+			testCase.Failure = &junitFailure{
+				Message: result.Error,
+				Type:    string(checker.StatusDown),
+				Content: result.Error,
+			}
+			suite.Failures++
+		case checker.StatusDegraded:
+			// Degradado não falha o teste, apenas fica registrado na saída
+			testCase.SystemOut = fmt.Sprintf("degraded: response time %dms", result.ResponseTime.Milliseconds())
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = formatSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
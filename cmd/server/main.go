@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,28 +15,86 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 
+	"github.com/luacarol/website-monitoring/internal/checker"
 	"github.com/luacarol/website-monitoring/internal/database"
 	"github.com/luacarol/website-monitoring/internal/handlers"
+	"github.com/luacarol/website-monitoring/internal/models"
 	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage/gormstore"
 )
 
+// Origens do dashboard fora do próprio servidor
+var allowedOrigins = []string{"http://localhost:3000"} // React dev server
+
 var (
 	monitorService *services.MonitorService
 	upgrader       = websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			return true // Permitir todas as origens em desenvolvimento
-		},
+		CheckOrigin: checkOrigin,
 	}
 )
 
+// Com login por cookie, o WebSocket só aceita o próprio servidor, o dashboard e clientes
+// fora do navegador (sem Origin)
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if parsed, err := url.Parse(origin); err == nil && parsed.Host == r.Host {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// IPs e CIDRs dos proxies reversos em TRUSTED_PROXIES, separados por vírgula. Sem a
+// variável, nenhum proxy é confiável e o IP do cliente é o da conexão
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func main() {
+	// Subcomandos administrativos
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "user" {
+		os.Exit(runUser(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "org" {
+		os.Exit(runOrg(os.Args[2:]))
+	}
+
+	// Destinos que o servidor pode verificar
+	policy, err := checker.PolicyFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Política de destinos inválida: %v", err)
+	}
+
 	// Inicializar banco de dados
-	database.InitDatabase()
+	store := gormstore.New(database.InitDatabase())
+	stats := services.NewStatsService(store)
+	authConfig := handlers.AuthConfigFromEnv()
+	api := handlers.New(store, stats, authConfig, policy)
 
 	// Inicializar serviço de monitoramento
-	monitorService = services.NewMonitorService()
+	monitorService = services.NewMonitorService(store, policy)
 	monitorService.Start()
 
+	// Consolidação e limpeza dos logs antigos
+	retentionService := services.NewRetentionService(store, stats, services.RetentionConfigFromEnv())
+	retentionService.Start()
+
 	// Configurar Gin
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.DebugMode)
@@ -42,38 +102,108 @@ func main() {
 
 	router := gin.Default()
 
+	// IP do cliente (auditoria): o X-Forwarded-For só vale quando vem de um proxy confiável
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES inválido: %v", err)
+	}
+
 	// Configurar CORS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"}, // React dev server
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "Content-Disposition"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Routes da API
-	api := router.Group("/api")
+	// Login do dashboard
+	authRoutes := router.Group("/api/auth", api.Authenticate)
+	{
+		authRoutes.POST("/login", api.Login)
+		authRoutes.POST("/logout", api.Logout)
+		authRoutes.GET("/me", api.Me)
+	}
+
+	// Routes da API: alterações exigem login ou chave de API com escopo write
+	routes := router.Group("/api", api.Authenticate, handlers.RequireAuth(authConfig.PublicRead))
 	{
+		// Organização da requisição, com cotas e uso
+		routes.GET("/organization", api.GetOrganization)
+
 		// Sites
-		api.GET("/sites", handlers.GetSites)
-		api.POST("/sites", handlers.CreateSite)
-		api.DELETE("/sites/:id", handlers.DeleteSite)
-		api.PUT("/sites/:id/toggle", handlers.ToggleSite)
+		routes.GET("/sites", api.GetSites)
+		routes.POST("/sites", api.CreateSite)
+		routes.GET("/sites/deleted", api.GetDeletedSites)
+		routes.POST("/sites/import", api.ImportSites)
+		routes.GET("/sites/export", api.ExportSites)
+		routes.GET("/sites/:id", api.GetSite)
+		routes.PUT("/sites/:id", api.UpdateSite)
+		routes.PATCH("/sites/:id", api.PatchSite)
+		routes.DELETE("/sites/:id", api.DeleteSite)
+		routes.PUT("/sites/:id/toggle", api.ToggleSite)
+		routes.POST("/sites/:id/restore", api.RestoreSite)
+		routes.DELETE("/sites/:id/purge", api.PurgeSite)
+		routes.GET("/sites/:id/uptime", api.GetSiteUptime)
+		routes.GET("/sites/:id/stats", api.GetSiteStats)
+
+		// Tags e grupos
+		routes.GET("/tags", api.GetTags)
+		routes.DELETE("/tags/:name", api.DeleteTag)
+		routes.GET("/groups", api.GetGroups)
+		routes.POST("/groups", api.CreateGroup)
+		routes.PUT("/groups/:id", api.UpdateGroup)
+		routes.DELETE("/groups/:id", api.DeleteGroup)
 
 		// Logs
-		api.GET("/logs", handlers.GetLogs)
+		routes.GET("/logs", api.GetLogs)
+		routes.GET("/logs/export", api.ExportLogs)
+
+		// Incidentes
+		routes.GET("/incidents", api.GetIncidents)
+		routes.POST("/incidents/:id/ack", api.AcknowledgeIncident)
 
 		// Stats
-		api.GET("/stats", handlers.GetStats)
+		routes.GET("/stats", api.GetStats)
+
+		// Auditoria das alterações (admins globais)
+		routes.GET("/audit", api.GetAudit)
+		routes.GET("/audit/export", api.ExportAudit)
 
 		// Monitor
-		api.POST("/monitor/check/:id", checkSiteNow)
-		api.GET("/monitor/status", getMonitorStatus)
+		routes.POST("/monitor/check/:id", func(c *gin.Context) {
+			checkSiteNow(c, api)
+		})
+		routes.GET("/monitor/status", getMonitorStatus)
+
+		// Usuários e chaves de API (apenas com login)
+		account := routes.Group("", handlers.RequireSession)
+		account.GET("/users", api.GetUsers)
+		account.POST("/users", api.CreateUser)
+		account.PUT("/users/:id/password", api.UpdatePassword)
+		account.DELETE("/users/:id", api.DeleteUser)
+		account.GET("/users/:id/roles", api.GetRoles)
+		account.PUT("/users/:id/roles", api.UpdateRoles)
+		account.GET("/keys", api.GetAPIKeys)
+		account.POST("/keys", api.CreateAPIKey)
+		account.DELETE("/keys/:id", api.DeleteAPIKey)
+	}
+
+	// WebSocket para updates em tempo real (sempre autenticado; ?group=ID para um grupo)
+	router.GET("/ws", api.Authenticate, handlers.RequireAuth(false), func(c *gin.Context) {
+		handleWebSocket(c, api)
+	})
+
+	// Página de status pública (organização padrão e demais pelo slug)
+	if statusPage := handlers.StatusPageConfigFromEnv(); statusPage.Path != "" {
+		router.GET(statusPage.Path, api.StatusPage(statusPage))
+		router.GET(statusPage.Path+"/:org", api.StatusPage(statusPage))
 	}
 
-	// WebSocket para updates em tempo real
-	router.GET("/ws", handleWebSocket)
+	// Badges SVG dos sites públicos
+	router.GET("/badge/:id/status.svg", api.StatusBadge)
+	router.GET("/badge/:id/uptime.svg", api.UptimeBadge)
+	router.GET("/badge/:id/response-time.svg", api.ResponseTimeBadge)
 
 	// Servir arquivos estáticos do React (quando buildado)
 	router.Static("/static", "./web/build/static")
@@ -87,6 +217,7 @@ func main() {
 		<-c
 		log.Println("\n🛑 Recebido sinal de parada...")
 		monitorService.Stop()
+		retentionService.Stop()
 		os.Exit(0)
 	}()
 
@@ -103,8 +234,8 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, router))
 }
 
-// Handler para verificar site imediatamente
-func checkSiteNow(c *gin.Context) {
+// Handler para verificar site imediatamente (papel operator no grupo do site)
+func checkSiteNow(c *gin.Context, api *handlers.Handler) {
 	siteID := c.Param("id")
 	if siteID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do site é obrigatório"})
@@ -118,11 +249,16 @@ func checkSiteNow(c *gin.Context) {
 		return
 	}
 
+	if !api.AuthorizeSite(c, id, models.RoleOperator) {
+		return
+	}
+
 	result := monitorService.CheckSiteNow(id)
 	if result == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
 		return
 	}
+	api.AuditSiteCheck(c, *result)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Verificação realizada",
@@ -139,7 +275,13 @@ func getMonitorStatus(c *gin.Context) {
 }
 
 // WebSocket para updates em tempo real
-func handleWebSocket(c *gin.Context) {
+func handleWebSocket(c *gin.Context, api *handlers.Handler) {
+	// Permissões conferidas antes do upgrade, para responder com o status HTTP
+	feed, ok := api.StatsFeed(c)
+	if !ok {
+		return
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("❌ Erro ao fazer upgrade WebSocket: %v", err)
@@ -155,7 +297,11 @@ func handleWebSocket(c *gin.Context) {
 
 	for range ticker.C {
 		// Enviar stats atualizadas
-		stats := getStatsData()
+		stats, err := getStatsData(feed)
+		if err != nil {
+			log.Printf("❌ Erro ao calcular stats: %v", err)
+			continue
+		}
 		if err := conn.WriteJSON(stats); err != nil {
 			log.Printf("❌ Erro ao enviar dados WebSocket: %v", err)
 			return
@@ -164,17 +310,15 @@ func handleWebSocket(c *gin.Context) {
 }
 
 // Helper para obter dados de stats
-func getStatsData() map[string]interface{} {
-	// Implementar lógica similar ao handlers.GetStats
-	// Por simplicidade, retornando dados mock aqui
+func getStatsData(feed func() (*models.StatsResponse, error)) (map[string]interface{}, error) {
+	stats, err := feed()
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
 		"type":      "stats_update",
 		"timestamp": time.Now(),
-		"data": map[string]interface{}{
-			"total_sites":    5,
-			"online_sites":   4,
-			"offline_sites":  1,
-			"overall_uptime": 95.5,
-		},
-	}
+		"data":      stats,
+	}, nil
 }
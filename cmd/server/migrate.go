@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/luacarol/website-monitoring/internal/database"
+	"github.com/luacarol/website-monitoring/internal/database/migrations"
+)
+
+// migrate up|down|status: gerenciar o esquema do banco configurado em DATABASE_URL
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Uso: monitor-server migrate up|down [-steps N]|status")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	steps := fs.Int("steps", 1, "quantidade de migrations a reverter (down)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	// "migrate down 12" reverteria só uma migration: a quantidade vem apenas de -steps
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Argumento inesperado: %s (use -steps N)\n", fs.Arg(0))
+		return 2
+	}
+	if *steps < 1 {
+		fmt.Fprintln(os.Stderr, "-steps deve ser maior que zero")
+		return 2
+	}
+
+	db, err := database.Open(database.DSNFromEnv())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Falha ao conectar com o banco de dados:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Printf("✅ %d migrations aplicadas (versão %d)\n", applied, migrations.Latest())
+
+	case "down":
+		reverted, err := migrations.Down(db, *steps)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		current, _ := migrations.Current(db)
+		fmt.Printf("✅ %d migrations revertidas (versão %d)\n", reverted, current)
+
+	case "status":
+		statuses, err := migrations.List(db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSÃO\tNOME\tSTATUS\tAPLICADA EM")
+		for _, status := range statuses {
+			state, appliedAt := "pendente", "-"
+			if status.Applied {
+				state = "aplicada"
+				appliedAt = status.AppliedAt.Local().Format("02/01/2006 15:04:05")
+			}
+			if status.Unknown {
+				state = "desconhecida"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		tw.Flush()
+
+	default:
+		fmt.Fprintln(os.Stderr, "Comando desconhecido:", args[0])
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/luacarol/website-monitoring/internal/database"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"github.com/luacarol/website-monitoring/internal/storage/gormstore"
+)
+
+const orgUsage = "Uso: monitor-server org list | add [-slug SLUG] [-max-sites N] [-min-interval S] NOME | set [-name NOME] [-max-sites N] [-min-interval S] SLUG"
+
+// org list, org add, org set: gerenciar as organizações (tenants) e as cotas de cada uma,
+// que só quem hospeda o monitor altera. Cotas em 0 ficam sem limite
+func runOrg(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, orgUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("org "+args[0], flag.ContinueOnError)
+	slug := fs.String("slug", "", "endereço da página de status (padrão: a partir do nome)")
+	name := fs.String("name", "", "novo nome (set)")
+	maxSites := fs.Int("max-sites", 0, "máximo de sites (0 para sem limite)")
+	minInterval := fs.Int("min-interval", 0, "intervalo mínimo entre checks em segundos (0 para sem limite)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if *maxSites < 0 {
+		fmt.Fprintln(os.Stderr, "❌ -max-sites não pode ser negativo")
+		return 2
+	}
+	if err := validMinInterval(*minInterval); err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 2
+	}
+
+	db := database.InitDatabase().Session(&gorm.Session{Logger: logger.Discard})
+	store := gormstore.New(db)
+
+	switch args[0] {
+	case "list":
+		organizations, err := store.ListOrganizations()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSLUG\tNOME\tSITES\tMÁX. SITES\tINTERVALO MÍN.")
+		for _, organization := range organizations {
+			sites, err := store.CountSites(organization.ID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "❌", err)
+				return 1
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\n", organization.ID, organization.Slug, organization.Name,
+				sites, quotaText(organization.MaxSites, ""), quotaText(organization.MinCheckIntervalSeconds, "s"))
+		}
+		tw.Flush()
+
+	case "add":
+		if fs.NArg() != 1 || strings.TrimSpace(fs.Arg(0)) == "" {
+			fmt.Fprintln(os.Stderr, orgUsage)
+			return 2
+		}
+
+		organization := models.Organization{
+			Name:                    strings.TrimSpace(fs.Arg(0)),
+			Slug:                    *slug,
+			MaxSites:                *maxSites,
+			MinCheckIntervalSeconds: *minInterval,
+		}
+		if organization.Slug == "" {
+			organization.Slug = slugFor(organization.Name)
+		}
+		if !models.ValidSlug(organization.Slug) {
+			fmt.Fprintf(os.Stderr, "❌ Slug inválido: %q (use letras minúsculas, números e hífens)\n", organization.Slug)
+			return 2
+		}
+
+		switch err := store.CreateOrganization(&organization); {
+		case errors.Is(err, storage.ErrDuplicate):
+			fmt.Fprintf(os.Stderr, "❌ A organização %s já existe\n", organization.Slug)
+			return 1
+		case err != nil:
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Printf("✅ Organização %s criada (#%d)\n", organization.Slug, organization.ID)
+
+	case "set":
+		if fs.NArg() != 1 || set["slug"] {
+			fmt.Fprintln(os.Stderr, orgUsage)
+			return 2
+		}
+
+		organization, err := store.FindOrganizationBySlug(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Organização %s não encontrada\n", fs.Arg(0))
+			return 1
+		}
+		if set["name"] && strings.TrimSpace(*name) != "" {
+			organization.Name = strings.TrimSpace(*name)
+		}
+		if set["max-sites"] {
+			organization.MaxSites = *maxSites
+		}
+		if set["min-interval"] {
+			organization.MinCheckIntervalSeconds = *minInterval
+		}
+
+		if err := store.UpdateOrganization(organization); err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Printf("✅ Organização %s atualizada\n", organization.Slug)
+
+	default:
+		fmt.Fprintln(os.Stderr, "Comando desconhecido:", args[0])
+		return 2
+	}
+
+	return 0
+}
+
+// O intervalo mínimo segue as regras do intervalo dos sites: múltiplo do intervalo padrão,
+// até uma hora
+func validMinInterval(seconds int) error {
+	step := int(services.CheckInterval.Seconds())
+	if seconds < 0 || seconds > 3600 || seconds%step != 0 {
+		return fmt.Errorf("-min-interval deve ser um múltiplo de %d entre 0 e 3600", step)
+	}
+	return nil
+}
+
+// Slug a partir do nome: minúsculas, com hífens no lugar do restante
+func slugFor(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func quotaText(value int, unit string) string {
+	if value == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%s", value, unit)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/luacarol/website-monitoring/internal/auth"
+	"github.com/luacarol/website-monitoring/internal/database"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"github.com/luacarol/website-monitoring/internal/storage/gormstore"
+)
+
+// user add [-org SLUG] NOME [PAPEL], user passwd NOME, user list: gerenciar os usuários sem
+// passar pela API (ex.: o primeiro usuário de cada organização). A senha é lida da entrada
+// padrão; o papel, global na organização, é admin quando omitido
+func runUser(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Uso: monitor-server user add [-org SLUG] NOME [viewer|operator|admin] | passwd NOME | list")
+		return 2
+	}
+
+	db := database.InitDatabase().Session(&gorm.Session{Logger: logger.Discard})
+	store := gormstore.New(db)
+
+	switch args[0] {
+	case "list":
+		users, err := store.ListUsers(0)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		organizations, err := store.ListOrganizations()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		slugs := make(map[uint]string, len(organizations))
+		for _, organization := range organizations {
+			slugs[organization.ID] = organization.Slug
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tUSUÁRIO\tORGANIZAÇÃO\tCRIADO EM")
+		for _, user := range users {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", user.ID, user.Username, slugs[user.OrganizationID], user.CreatedAt.Local().Format("02/01/2006 15:04:05"))
+		}
+		tw.Flush()
+
+	case "add", "passwd":
+		fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+		slug := fs.String("org", "default", "slug da organização do novo usuário (add)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		rest := fs.Args()
+		if len(rest) != 1 && (args[0] != "add" || len(rest) != 2) {
+			fmt.Fprintf(os.Stderr, "Uso: monitor-server user %s NOME\n", args[0])
+			return 2
+		}
+
+		role := models.RoleAdmin
+		if len(rest) == 2 {
+			role = rest[1]
+			if !models.ValidRole(role) {
+				fmt.Fprintln(os.Stderr, "❌ Papel inválido:", role)
+				return 2
+			}
+		}
+
+		var organization *models.Organization
+		if args[0] == "add" {
+			var err error
+			if organization, err = store.FindOrganizationBySlug(*slug); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Organização %s não encontrada\n", *slug)
+				return 1
+			}
+		}
+
+		hash, err := readPassword()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+
+		if args[0] == "add" {
+			user := models.User{OrganizationID: organization.ID, Username: rest[0], PasswordHash: hash}
+			switch err := store.CreateUser(&user); {
+			case errors.Is(err, storage.ErrDuplicate):
+				fmt.Fprintf(os.Stderr, "❌ O usuário %s já existe\n", rest[0])
+				return 1
+			case err != nil:
+				fmt.Fprintln(os.Stderr, "❌", err)
+				return 1
+			}
+			if err := store.ReplaceRoles(user.ID, []models.RoleBinding{{Role: role}}); err != nil {
+				fmt.Fprintln(os.Stderr, "❌", err)
+				return 1
+			}
+			fmt.Printf("✅ Usuário %s criado (#%d) em %s com o papel %s\n", user.Username, user.ID, organization.Slug, role)
+			return 0
+		}
+
+		user, err := store.FindUserByUsername(rest[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Usuário %s não encontrado\n", rest[0])
+			return 1
+		}
+		if err := store.UpdatePassword(user.ID, hash); err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Printf("✅ Senha de %s alterada\n", user.Username)
+
+	default:
+		fmt.Fprintln(os.Stderr, "Comando desconhecido:", args[0])
+		return 2
+	}
+
+	return 0
+}
+
+// Ler a senha da primeira linha da entrada padrão e gerar o hash
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Senha: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("informe a senha na entrada padrão")
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < 8 || len(password) > 72 {
+		return "", errors.New("a senha deve ter de 8 a 72 caracteres")
+	}
+	return auth.HashPassword(password)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Prefixo das chaves de API, para reconhecê-las em arquivos de configuração e logs
+const KeyPrefix = "wm_"
+
+// Caracteres da chave exibidos na listagem (após o prefixo)
+const visiblePrefix = 8
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Hash de comparação para quando o usuário não existe, para que o login leve o mesmo tempo
+var dummyHash, _ = HashPassword("senha-inexistente")
+
+func CheckMissingPassword(password string) {
+	CheckPassword(dummyHash, password)
+}
+
+// Token aleatório de sessão (256 bits)
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Nova chave de API e o início exibido na listagem
+func NewKey() (key, prefix string, err error) {
+	token, err := NewToken()
+	if err != nil {
+		return "", "", err
+	}
+	key = KeyPrefix + token
+	return key, key[:len(KeyPrefix)+visiblePrefix], nil
+}
+
+// Hash guardado no banco para tokens e chaves. Eles já são aleatórios, então SHA-256 basta
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
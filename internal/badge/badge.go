@@ -0,0 +1,62 @@
+package badge
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Cores do estilo flat do shields.io
+const (
+	Green       = "#4c1"
+	YellowGreen = "#a4a61d"
+	Yellow      = "#dfb317"
+	Orange      = "#fe7d37"
+	Red         = "#e05d44"
+	Grey        = "#9f9f9f"
+)
+
+const template = `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[3]s: %[4]s">` +
+	`<title>%[3]s: %[4]s</title>` +
+	`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+	`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>` +
+	`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[5]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>` +
+	`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+	`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[7]d" y="14">%[3]s</text>` +
+	`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[8]d" y="14">%[4]s</text>` +
+	`</g></svg>`
+
+// Badge no formato "label | message", com a mensagem sobre a cor informada
+func Render(label, message, color string) []byte {
+	labelWidth := textWidth(label) + 10
+	messageWidth := textWidth(message) + 10
+
+	return []byte(fmt.Sprintf(template,
+		labelWidth+messageWidth,
+		labelWidth,
+		html.EscapeString(label),
+		html.EscapeString(message),
+		messageWidth,
+		html.EscapeString(color),
+		labelWidth/2,
+		labelWidth+messageWidth/2,
+	))
+}
+
+// Largura aproximada do texto em Verdana 11px
+func textWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("ijlI.,:;|!' ", r):
+			width += 3.5
+		case strings.ContainsRune("mwMW%", r):
+			width += 10
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 6.5
+		}
+	}
+	return int(width + 0.5)
+}
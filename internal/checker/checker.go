@@ -0,0 +1,206 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Tipos de verificação suportados
+type Type string
+
+const (
+	TypeHTTP Type = "http"
+	TypeTCP  Type = "tcp"
+	TypeDNS  Type = "dns"
+)
+
+// Resultado classificado de uma verificação
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// Configuração de uma verificação. Target é uma URL: http(s)://, tcp://host:porta ou dns://host
+type Config struct {
+	Type           Type
+	Target         string
+	Timeout        time.Duration
+	ExpectedStatus string        // Ex.: "200-399" ou "200,301-302" (padrão: 200-399)
+	Keyword        string        // Texto que deve aparecer no corpo da resposta HTTP
+	DegradedAfter  time.Duration // Tempo de resposta acima do qual o alvo fica degradado (0 desativa)
+}
+
+type Result struct {
+	Type         Type          `json:"check_type"`
+	Target       string        `json:"target"`
+	Status       Status        `json:"status"`
+	StatusCode   int           `json:"status_code"`
+	ResponseTime time.Duration `json:"-"`
+	Error        string        `json:"error,omitempty"`
+	CheckedAt    time.Time     `json:"checked_at"`
+}
+
+// O alvo respondeu, mesmo que degradado
+func (r Result) IsOnline() bool {
+	return r.Status != StatusDown
+}
+
+// Checker executa verificações; o mesmo valor pode ser usado em paralelo
+type Checker struct {
+	dialer *net.Dialer
+	policy *Policy // nil para qualquer destino
+}
+
+// Checker sem restrição de destino, para verificar a partir da máquina do usuário (CLI)
+func New() *Checker {
+	return NewWithPolicy(nil)
+}
+
+// Checker que só conecta aos destinos permitidos pela política (servidor)
+func NewWithPolicy(policy *Policy) *Checker {
+	return &Checker{
+		dialer: &net.Dialer{},
+		policy: policy,
+	}
+}
+
+// Conectar respeitando a política: o host é conferido antes da conexão e cada endereço
+// resolvido, no momento da conexão, para que o DNS rebinding não a contorne
+func (c *Checker) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	if c.policy == nil {
+		return c.dialer.DialContext(ctx, network, address)
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := c.policy.checkHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := *c.dialer
+	dialer.Control = func(_, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return fmt.Errorf("%w: endereço %s", ErrBlocked, address)
+		}
+		return c.policy.checkAddr(addrPort.Addr(), allowed)
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Inferir o tipo de verificação pelo esquema da URL
+func TypeFor(target string) Type {
+	switch {
+	case strings.HasPrefix(target, "tcp://"):
+		return TypeTCP
+	case strings.HasPrefix(target, "dns://"):
+		return TypeDNS
+	default:
+		return TypeHTTP
+	}
+}
+
+// Validar a configuração antes de salvar ou executar
+func (cfg Config) Validate() error {
+	if cfg.Target == "" {
+		return errors.New("alvo da verificação é obrigatório")
+	}
+
+	checkType := cfg.Type
+	if checkType == "" {
+		checkType = TypeFor(cfg.Target)
+	}
+
+	switch checkType {
+	case TypeHTTP:
+		if _, err := ParseStatusRanges(cfg.ExpectedStatus); err != nil {
+			return err
+		}
+	case TypeTCP, TypeDNS:
+		if _, err := hostFromTarget(cfg.Target); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("tipo de verificação desconhecido: %s", checkType)
+	}
+
+	if cfg.Timeout < 0 || cfg.DegradedAfter < 0 {
+		return errors.New("tempos não podem ser negativos")
+	}
+
+	return nil
+}
+
+// Executar a verificação de acordo com o tipo
+func (c *Checker) Check(ctx context.Context, cfg Config) Result {
+	if cfg.Type == "" {
+		cfg.Type = TypeFor(cfg.Target)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	result := Result{
+		Type:      cfg.Type,
+		Target:    cfg.Target,
+		CheckedAt: time.Now(),
+	}
+
+	startTime := time.Now()
+
+	var err error
+	switch cfg.Type {
+	case TypeHTTP:
+		err = c.checkHTTP(ctx, cfg, &result)
+	case TypeTCP:
+		err = c.checkTCP(ctx, cfg)
+	case TypeDNS:
+		err = c.checkDNS(ctx, cfg)
+	default:
+		err = fmt.Errorf("tipo de verificação desconhecido: %s", cfg.Type)
+	}
+
+	result.ResponseTime = time.Since(startTime)
+
+	switch {
+	case err != nil:
+		result.Status = StatusDown
+		result.Error = err.Error()
+	case cfg.DegradedAfter > 0 && result.ResponseTime > cfg.DegradedAfter:
+		result.Status = StatusDegraded
+	default:
+		result.Status = StatusUp
+	}
+
+	return result
+}
+
+// Extrair host (e porta, quando houver) de tcp://host:porta ou dns://host
+func hostFromTarget(target string) (string, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("alvo inválido: %w", err)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("alvo sem host: %s", target)
+	}
+	return parsed.Host, nil
+}
@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Limite de leitura do corpo para a verificação de palavra-chave
+const maxBodySize = 1 << 20
+
+func (c *Checker) checkHTTP(ctx context.Context, cfg Config, result *Result) error {
+	ranges, err := ParseStatusRanges(cfg.ExpectedStatus)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.Target, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("User-Agent", "website-monitor")
+
+	// Com proxy, a conexão é com o proxy configurado no servidor, que resolve o destino: o
+	// host é resolvido aqui e todos os endereços são conferidos em cada request, inclusive
+	// nos redirecionamentos. Sem proxy, cada conexão passa pela política
+	var proxies sync.Map
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: func(request *http.Request) (*url.URL, error) {
+				proxyURL, err := http.ProxyFromEnvironment(request)
+				if err != nil || proxyURL == nil {
+					return proxyURL, err
+				}
+				if err := c.policy.checkResolved(request.Context(), request.URL.Hostname()); err != nil {
+					return nil, err
+				}
+				proxies.Store(proxyAddr(proxyURL), true)
+				return proxyURL, nil
+			},
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				if _, ok := proxies.Load(address); ok {
+					return c.dialer.DialContext(ctx, network, address)
+				}
+				return c.dial(ctx, network, address)
+			},
+			TLSHandshakeTimeout: cfg.Timeout,
+			DisableKeepAlives:   true,
+		},
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	result.StatusCode = response.StatusCode
+
+	if !ranges.Contains(response.StatusCode) {
+		return fmt.Errorf("status code inesperado: %d", response.StatusCode)
+	}
+
+	if cfg.Keyword != "" {
+		body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("erro ao ler resposta: %w", err)
+		}
+		if !strings.Contains(string(body), cfg.Keyword) {
+			return fmt.Errorf("palavra-chave não encontrada: %q", cfg.Keyword)
+		}
+	}
+
+	return nil
+}
+
+// host:porta da conexão com o proxy, com a porta padrão do esquema
+func proxyAddr(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443", "socks5": "1080", "socks5h": "1080"}[proxyURL.Scheme]
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
@@ -0,0 +1,41 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+func (c *Checker) checkTCP(ctx context.Context, cfg Config) error {
+	host, err := hostFromTarget(cfg.Target)
+	if err != nil {
+		return err
+	}
+
+	conn, err := c.dial(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (c *Checker) checkDNS(ctx context.Context, cfg Config) error {
+	host, err := hostFromTarget(cfg.Target)
+	if err != nil {
+		return err
+	}
+
+	// Sem conexão ao alvo, valem apenas as regras de domínio
+	if _, err := c.policy.checkHost(host); err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("nenhum endereço encontrado para %s", host)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Conexão recusada pela política de destinos
+var ErrBlocked = errors.New("destino bloqueado pela política")
+
+// Faixas bloqueadas por padrão: a própria máquina, redes internas, metadados de nuvem
+// (169.254.169.254) e faixas que não são de destinos únicos da internet
+var blockedRanges = []struct {
+	prefix netip.Prefix
+	reason string
+}{
+	{netip.MustParsePrefix("0.0.0.0/8"), "rede local"},
+	{netip.MustParsePrefix("10.0.0.0/8"), "rede privada"},
+	{netip.MustParsePrefix("100.64.0.0/10"), "rede compartilhada"},
+	{netip.MustParsePrefix("127.0.0.0/8"), "loopback"},
+	{netip.MustParsePrefix("169.254.0.0/16"), "link-local"},
+	{netip.MustParsePrefix("172.16.0.0/12"), "rede privada"},
+	{netip.MustParsePrefix("192.168.0.0/16"), "rede privada"},
+	{netip.MustParsePrefix("198.18.0.0/15"), "rede de testes"},
+	{netip.MustParsePrefix("224.0.0.0/4"), "multicast"},
+	{netip.MustParsePrefix("240.0.0.0/4"), "reservado"},
+	{netip.MustParsePrefix("::/128"), "rede local"},
+	{netip.MustParsePrefix("::1/128"), "loopback"},
+	{netip.MustParsePrefix("64:ff9b:1::/48"), "NAT64 local"},
+	{netip.MustParsePrefix("fc00::/7"), "rede privada"},
+	{netip.MustParsePrefix("fe80::/10"), "link-local"},
+	{netip.MustParsePrefix("ff00::/8"), "multicast"},
+}
+
+// Prefixos IPv6 que carregam um IPv4 (NAT64 e 6to4), conferido como o próprio IPv4
+var (
+	nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+	sixToFour   = netip.MustParsePrefix("2002::/16")
+)
+
+// IPv4 embutido no endereço de NAT64 (últimos 4 bytes) ou 6to4 (bytes 2 a 5)
+func embeddedIPv4(addr netip.Addr) (netip.Addr, bool) {
+	bytes := addr.As16()
+	switch {
+	case nat64Prefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(bytes[12:16])), true
+	case sixToFour.Contains(addr):
+		return netip.AddrFrom4([4]byte(bytes[2:6])), true
+	}
+	return netip.Addr{}, false
+}
+
+// Política dos destinos que o servidor pode verificar. As listas aceitam IPs, CIDRs e
+// domínios ("example.com" ou "*.example.com" para os subdomínios). A negação vence a
+// permissão, e a permissão libera as faixas bloqueadas por padrão. Uma política nil
+// permite tudo
+type Policy struct {
+	allowPrivate bool // Não bloquear as faixas internas por padrão
+	allow        []targetRule
+	deny         []targetRule
+}
+
+type targetRule struct {
+	prefix   netip.Prefix
+	domain   string // Sem o "*." quando wildcard é true
+	wildcard bool
+}
+
+// Montar a política a partir das listas separadas por vírgula
+func NewPolicy(allow, deny string, allowPrivate bool) (*Policy, error) {
+	policy := &Policy{allowPrivate: allowPrivate}
+	var err error
+	if policy.allow, err = parseRules(allow); err != nil {
+		return nil, fmt.Errorf("lista de permissão: %w", err)
+	}
+	if policy.deny, err = parseRules(deny); err != nil {
+		return nil, fmt.Errorf("lista de negação: %w", err)
+	}
+	return policy, nil
+}
+
+// TARGET_ALLOW e TARGET_DENY com as listas e TARGET_ALLOW_PRIVATE=true para não bloquear
+// as faixas internas
+func PolicyFromEnv() (*Policy, error) {
+	return NewPolicy(os.Getenv("TARGET_ALLOW"), os.Getenv("TARGET_DENY"), os.Getenv("TARGET_ALLOW_PRIVATE") == "true")
+}
+
+func parseRules(list string) ([]targetRule, error) {
+	var rules []targetRule
+	for _, item := range strings.Split(list, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("CIDR inválido: %q", item)
+			}
+			rules = append(rules, targetRule{prefix: prefix.Masked()})
+			continue
+		}
+		if addr, err := netip.ParseAddr(item); err == nil {
+			addr = addr.Unmap()
+			rules = append(rules, targetRule{prefix: netip.PrefixFrom(addr, addr.BitLen())})
+			continue
+		}
+
+		domain, wildcard := strings.CutPrefix(item, "*.")
+		domain = strings.TrimSuffix(domain, ".")
+		if domain == "" || strings.ContainsAny(domain, "*:") {
+			return nil, fmt.Errorf("domínio inválido: %q", item)
+		}
+		rules = append(rules, targetRule{domain: domain, wildcard: wildcard})
+	}
+	return rules, nil
+}
+
+func (r targetRule) matchesAddr(addr netip.Addr) bool {
+	return r.prefix.IsValid() && r.prefix.Contains(addr)
+}
+
+func (r targetRule) matchesHost(host string) bool {
+	if r.domain == "" {
+		return false
+	}
+	if r.wildcard {
+		return strings.HasSuffix(host, "."+r.domain)
+	}
+	return host == r.domain
+}
+
+// Conferir o host pelas regras de domínio, ou o endereço quando o host é um IP. Retorna se
+// o domínio está na lista de permissão, o que libera os endereços internos dele
+func (p *Policy) checkHost(host string) (bool, error) {
+	if p == nil {
+		return true, nil
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return false, p.checkAddr(addr, false)
+	}
+
+	for _, rule := range p.deny {
+		if rule.matchesHost(host) {
+			return false, fmt.Errorf("%w: %s (lista de negação)", ErrBlocked, host)
+		}
+	}
+	for _, rule := range p.allow {
+		if rule.matchesHost(host) {
+			return true, nil
+		}
+	}
+	if !p.allowPrivate && (host == "localhost" || strings.HasSuffix(host, ".localhost")) {
+		return false, fmt.Errorf("%w: %s (loopback)", ErrBlocked, host)
+	}
+	return false, nil
+}
+
+// Conferir o endereço de uma conexão. allowed vem do domínio permitido
+func (p *Policy) checkAddr(addr netip.Addr, allowed bool) error {
+	if p == nil {
+		return nil
+	}
+
+	addr = addr.Unmap().WithZone("")
+	if embedded, ok := embeddedIPv4(addr); ok {
+		if err := p.checkAddr(embedded, allowed); err != nil {
+			return fmt.Errorf("%w (via %s)", err, addr)
+		}
+	}
+	for _, rule := range p.deny {
+		if rule.matchesAddr(addr) {
+			return fmt.Errorf("%w: %s (lista de negação)", ErrBlocked, addr)
+		}
+	}
+	if allowed || p.allowPrivate {
+		return nil
+	}
+	for _, rule := range p.allow {
+		if rule.matchesAddr(addr) {
+			return nil
+		}
+	}
+	for _, blocked := range blockedRanges {
+		if blocked.prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s (%s)", ErrBlocked, addr, blocked.reason)
+		}
+	}
+	return nil
+}
+
+// Conferir o host e todos os endereços para os quais ele resolve. Com proxy, a conexão é
+// com o proxy e o destino nunca passa pelo dial: sem esta conferência, um nome que resolve
+// para um endereço interno seria acessado pelo proxy
+func (p *Policy) checkResolved(ctx context.Context, host string) error {
+	allowed, err := p.checkHost(host)
+	if err != nil || p == nil || !p.blocksAddrs(allowed) {
+		return err
+	}
+
+	host = strings.Trim(host, "[]")
+	if _, err := netip.ParseAddr(host); err == nil {
+		// IP literal, já conferido por checkHost
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: não foi possível resolver %s para conferir o destino: %v", ErrBlocked, host, err)
+	}
+	for _, addr := range addrs {
+		if err := p.checkAddr(addr, allowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Algum endereço pode ser recusado: as faixas internas estão bloqueadas ou a lista de
+// negação tem IPs. Caso contrário não é preciso resolver o host para conferi-lo
+func (p *Policy) blocksAddrs(allowed bool) bool {
+	if !allowed && !p.allowPrivate {
+		return true
+	}
+	for _, rule := range p.deny {
+		if rule.prefix.IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+// Conferir o alvo de uma verificação antes de salvá-lo, sem resolver o DNS: IPs literais e
+// regras de domínio. Os endereços resolvidos são conferidos a cada conexão
+func (p *Policy) CheckTarget(target string) error {
+	if p == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Hostname() == "" {
+		// A validação da configuração reporta o alvo inválido
+		return nil
+	}
+	_, err = p.checkHost(parsed.Hostname())
+	return err
+}
@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const DefaultExpectedStatus = "200-399"
+
+type statusRange struct {
+	min, max int
+}
+
+// Lista de faixas de status code aceitas como sucesso
+type StatusRanges []statusRange
+
+// Interpretar expressões como "200-299,301". Vazio equivale a DefaultExpectedStatus
+func ParseStatusRanges(expr string) (StatusRanges, error) {
+	if strings.TrimSpace(expr) == "" {
+		expr = DefaultExpectedStatus
+	}
+
+	var ranges StatusRanges
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+
+		minText, maxText, isRange := strings.Cut(part, "-")
+		if !isRange {
+			maxText = minText
+		}
+
+		min, err := strconv.Atoi(strings.TrimSpace(minText))
+		if err != nil {
+			return nil, fmt.Errorf("status code esperado inválido: %q", part)
+		}
+		max, err := strconv.Atoi(strings.TrimSpace(maxText))
+		if err != nil {
+			return nil, fmt.Errorf("status code esperado inválido: %q", part)
+		}
+		if min < 100 || max > 599 || min > max {
+			return nil, fmt.Errorf("faixa de status code inválida: %q", part)
+		}
+
+		ranges = append(ranges, statusRange{min: min, max: max})
+	}
+
+	return ranges, nil
+}
+
+func (r StatusRanges) Contains(code int) bool {
+	for _, sr := range r {
+		if code >= sr.min && code <= sr.max {
+			return true
+		}
+	}
+	return false
+}
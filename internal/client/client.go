@@ -0,0 +1,237 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+)
+
+// Client para as rotas /api do servidor de monitoramento
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Erro retornado pela API no formato {"error": "..."}
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+func New(serverURL, apiKey string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(serverURL, "/") + "/api",
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type SitesResponse struct {
+	Sites []models.SiteResponse `json:"sites"`
+	Total int                   `json:"total"`
+}
+
+type SiteMessage struct {
+	Message string      `json:"message"`
+	Site    models.Site `json:"site"`
+}
+
+type CheckResponse struct {
+	Message string            `json:"message"`
+	Result  models.MonitorLog `json:"result"`
+}
+
+type LogsResponse struct {
+	Logs  []models.MonitorLog `json:"logs"`
+	Total int64               `json:"total"`
+	Page  int                 `json:"page"`
+	Limit int                 `json:"limit"`
+	Pages int64               `json:"pages"`
+
+	// Próxima página por cursor (vazio na última)
+	NextCursor string `json:"next_cursor"`
+}
+
+// GET /api/sites
+func (c *Client) ListSites() (*SitesResponse, error) {
+	var response SitesResponse
+	err := c.do(http.MethodGet, "/sites", nil, nil, &response)
+	return &response, err
+}
+
+// POST /api/sites
+func (c *Client) CreateSite(request models.SiteRequest) (*SiteMessage, error) {
+	var response SiteMessage
+	err := c.do(http.MethodPost, "/sites", nil, request, &response)
+	return &response, err
+}
+
+// POST /api/sites/import: format é json, csv ou txt; onConflict é skip, update ou fail
+func (c *Client) ImportSites(body io.Reader, format, onConflict string, dryRun bool) (*models.ImportResult, error) {
+	params := url.Values{}
+	params.Set("format", format)
+	if onConflict != "" {
+		params.Set("on_conflict", onConflict)
+	}
+	if dryRun {
+		params.Set("dry_run", "true")
+	}
+
+	var response models.ImportResult
+	err := c.send(http.MethodPost, "/sites/import", params, body, "text/plain", &response)
+	return &response, err
+}
+
+// GET /api/sites/export: copia o arquivo exportado para w
+func (c *Client) ExportSites(w io.Writer, format string) error {
+	params := url.Values{}
+	params.Set("format", format)
+	return c.send(http.MethodGet, "/sites/export", params, nil, "", w)
+}
+
+// DELETE /api/sites/:id
+func (c *Client) DeleteSite(id uint) error {
+	return c.do(http.MethodDelete, "/sites/"+strconv.FormatUint(uint64(id), 10), nil, nil, nil)
+}
+
+// PUT /api/sites/:id/toggle
+func (c *Client) ToggleSite(id uint) (*SiteMessage, error) {
+	var response SiteMessage
+	err := c.do(http.MethodPut, "/sites/"+strconv.FormatUint(uint64(id), 10)+"/toggle", nil, nil, &response)
+	return &response, err
+}
+
+// POST /api/monitor/check/:id
+func (c *Client) CheckSite(id uint) (*CheckResponse, error) {
+	var response CheckResponse
+	err := c.do(http.MethodPost, "/monitor/check/"+strconv.FormatUint(uint64(id), 10), nil, nil, &response)
+	return &response, err
+}
+
+// GET /api/logs
+func (c *Client) GetLogs(query models.LogsQuery) (*LogsResponse, error) {
+	params := url.Values{}
+	if query.SiteID != 0 {
+		params.Set("site_id", strconv.FormatUint(uint64(query.SiteID), 10))
+	}
+	if query.StartDate != "" {
+		params.Set("start_date", query.StartDate)
+	}
+	if query.EndDate != "" {
+		params.Set("end_date", query.EndDate)
+	}
+	if query.Status != "" {
+		params.Set("status", query.Status)
+	}
+	if query.StatusCode != "" {
+		params.Set("status_code", query.StatusCode)
+	}
+	if query.MinResponseTime != nil {
+		params.Set("min_response_time", strconv.FormatInt(*query.MinResponseTime, 10))
+	}
+	if query.MaxResponseTime != nil {
+		params.Set("max_response_time", strconv.FormatInt(*query.MaxResponseTime, 10))
+	}
+	if query.Error != "" {
+		params.Set("error", query.Error)
+	}
+	if query.CheckType != "" {
+		params.Set("check_type", query.CheckType)
+	}
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
+	if query.Page > 0 {
+		params.Set("page", strconv.Itoa(query.Page))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	var response LogsResponse
+	err := c.do(http.MethodGet, "/logs", params, nil, &response)
+	return &response, err
+}
+
+// GET /api/stats
+func (c *Client) GetStats() (*models.StatsResponse, error) {
+	var response models.StatsResponse
+	err := c.do(http.MethodGet, "/stats", nil, nil, &response)
+	return &response, err
+}
+
+// Executar a requisição e decodificar a resposta JSON em out (quando não for nil)
+func (c *Client) do(method, path string, params url.Values, body, out interface{}) error {
+	if body == nil {
+		return c.send(method, path, params, nil, "", out)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.send(method, path, params, bytes.NewReader(payload), "application/json", out)
+}
+
+// Executar a requisição com o corpo já serializado. Quando out é um io.Writer a resposta
+// é copiada sem decodificar
+func (c *Client) send(method, path string, params url.Values, body io.Reader, contentType string, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	request, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if c.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: response.StatusCode, Message: response.Status}
+		var errorBody struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(response.Body).Decode(&errorBody) == nil && errorBody.Error != "" {
+			apiErr.Message = errorBody.Error
+		}
+		return apiErr
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case io.Writer:
+		_, err := io.Copy(out, response.Body)
+		return err
+	default:
+		return json.NewDecoder(response.Body).Decode(out)
+	}
+}
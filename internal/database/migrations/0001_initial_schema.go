@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Esquema equivalente ao que o AutoMigrate criava. Os tipos são cópias congeladas
+// dos models nesta versão, para que mudanças futuras nos models não alterem a migration.
+// Em bancos criados pelo AutoMigrate, o AutoMigrate destes tipos não altera nada.
+
+type site0001 struct {
+	ID                  uint   `gorm:"primaryKey"`
+	Name                string `gorm:"not null"`
+	URL                 string `gorm:"not null;unique"`
+	Active              bool   `gorm:"default:true"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+	CheckType           string         `gorm:"default:http"`
+	ExpectedStatus      string
+	Keyword             string
+	TimeoutSeconds      int
+	DegradedThresholdMs int64
+
+	Logs []monitorLog0001 `gorm:"foreignKey:SiteID"`
+}
+
+func (site0001) TableName() string { return "sites" }
+
+type monitorLog0001 struct {
+	ID           uint `gorm:"primaryKey"`
+	SiteID       uint `gorm:"not null"`
+	StatusCode   int
+	ResponseTime int64
+	IsOnline     bool
+	Status       string
+	CheckType    string
+	ErrorMessage string
+	CheckedAt    time.Time
+	CreatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (monitorLog0001) TableName() string { return "monitor_logs" }
+
+type incident0001 struct {
+	ID         uint `gorm:"primaryKey"`
+	SiteID     uint `gorm:"not null;index"`
+	StartedAt  time.Time
+	ResolvedAt *time.Time
+	Cause      string
+	StatusCode int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	Site site0001 `gorm:"foreignKey:SiteID"`
+}
+
+func (incident0001) TableName() string { return "incidents" }
+
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&site0001{}, &monitorLog0001{}, &incident0001{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable("incidents", "monitor_logs", "sites")
+	},
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type logRollup0002 struct {
+	ID              uint      `gorm:"primaryKey"`
+	SiteID          uint      `gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	Period          string    `gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	BucketStart     time.Time `gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	Checks          int64
+	Successes       int64
+	MinResponseTime int64
+	AvgResponseTime int64
+	MaxResponseTime int64
+	P50ResponseTime int64
+	P90ResponseTime int64
+	P95ResponseTime int64
+	P99ResponseTime int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (logRollup0002) TableName() string { return "log_rollups" }
+
+var logRollups = Migration{
+	Version: 2,
+	Name:    "log_rollups",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&logRollup0002{}); err != nil {
+			return err
+		}
+		// Consultas de checks por período
+		return tx.Exec("CREATE INDEX IF NOT EXISTS idx_monitor_logs_site_checked ON monitor_logs (site_id, checked_at)").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_monitor_logs_site_checked").Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable("log_rollups")
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type logRollup0003 struct {
+	UpTimeMs   int64
+	DownTimeMs int64
+}
+
+func (logRollup0003) TableName() string { return "log_rollups" }
+
+// Tempo em cada estado por bucket, para o uptime ponderado pelo tempo.
+// Agregados anteriores ficam com zero, ou seja, estado desconhecido
+var rollupDurations = Migration{
+	Version: 3,
+	Name:    "rollup_durations",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []string{"UpTimeMs", "DownTimeMs"} {
+			if err := tx.Migrator().AddColumn(&logRollup0003{}, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, column := range []string{"UpTimeMs", "DownTimeMs"} {
+			if err := tx.Migrator().DropColumn(&logRollup0003{}, column); err != nil {
+				return err
+			}
+		}
+		// Índice único usado pelo ON CONFLICT de SaveRollups
+		return restoreIndexes(tx, &logRollup0002{}, "idx_rollup_bucket")
+	},
+}
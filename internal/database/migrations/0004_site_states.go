@@ -0,0 +1,109 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type siteState0004 struct {
+	SiteID          uint `gorm:"primaryKey;autoIncrement:false"`
+	Status          string
+	IsOnline        bool
+	StatusCode      int
+	ResponseTime    int64
+	ErrorMessage    string
+	CheckedAt       time.Time
+	StateSince      time.Time
+	Uptime24h       *float64
+	Uptime7d        *float64
+	Uptime30d       *float64
+	Uptime90d       *float64
+	UptimeUpdatedAt *time.Time
+	UpdatedAt       time.Time
+}
+
+func (siteState0004) TableName() string { return "site_states" }
+
+// Estado atual por site para a listagem, preenchido a partir do último log de cada site.
+// Os uptimes ficam vazios até a primeira execução do job periódico
+var siteStates = Migration{
+	Version: 4,
+	Name:    "site_states",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&siteState0004{}); err != nil {
+			return err
+		}
+
+		var sites []site0001
+		if err := tx.Select("id, created_at").Find(&sites).Error; err != nil {
+			return err
+		}
+
+		for _, site := range sites {
+			siteID := site.ID
+			var latest monitorLog0001
+			err := tx.Where("site_id = ?", siteID).Order("checked_at desc").First(&latest).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			since, err := stateSince0004(tx, siteID, latest.IsOnline)
+			if err != nil {
+				return err
+			}
+
+			state := siteState0004{
+				SiteID:       siteID,
+				Status:       latest.Status,
+				IsOnline:     latest.IsOnline,
+				StatusCode:   latest.StatusCode,
+				ResponseTime: latest.ResponseTime,
+				ErrorMessage: latest.ErrorMessage,
+				CheckedAt:    latest.CheckedAt,
+				StateSince:   since,
+			}
+			// Sem incidente: online desde o cadastro, ou offline desde o último check
+			if since.IsZero() {
+				state.StateSince = latest.CheckedAt
+				if latest.IsOnline {
+					state.StateSince = site.CreatedAt
+				}
+			}
+			if err := tx.Create(&state).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable("site_states")
+	},
+}
+
+// Última transição entre online e offline pelos incidentes (zero quando não há)
+func stateSince0004(tx *gorm.DB, siteID uint, online bool) (time.Time, error) {
+	var incident incident0001
+	query := tx.Where("site_id = ?", siteID).Order("started_at desc")
+	if online {
+		query = query.Where("resolved_at IS NOT NULL")
+	} else {
+		query = query.Where("resolved_at IS NULL")
+	}
+
+	err := query.First(&incident).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return time.Time{}, nil
+	case err != nil:
+		return time.Time{}, err
+	case online:
+		return *incident.ResolvedAt, nil
+	default:
+		return incident.StartedAt, nil
+	}
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type site0005 struct {
+	Version int `gorm:"not null;default:1"`
+}
+
+func (site0005) TableName() string { return "sites" }
+
+// Versão de cada site para o ETag e o controle de concorrência nas edições
+var siteVersion = Migration{
+	Version: 5,
+	Name:    "site_version",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&site0005{}, "Version")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&site0005{}, "Version"); err != nil {
+			return err
+		}
+		return restoreIndexes(tx, &site0001{}, "DeletedAt")
+	},
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// Paginação por cursor dos logs, ordenada por (checked_at, id)
+var logKeysetIndex = Migration{
+	Version: 6,
+	Name:    "log_keyset_index",
+	Up: func(tx *gorm.DB) error {
+		return tx.Exec("CREATE INDEX IF NOT EXISTS idx_monitor_logs_checked_id ON monitor_logs (checked_at, id)").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec("DROP INDEX IF EXISTS idx_monitor_logs_checked_id").Error
+	},
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tag0007 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+func (tag0007) TableName() string { return "tags" }
+
+type siteTag0007 struct {
+	SiteID uint `gorm:"primaryKey"`
+	TagID  uint `gorm:"primaryKey;index"`
+}
+
+func (siteTag0007) TableName() string { return "site_tags" }
+
+type group0007 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"not null;uniqueIndex"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (group0007) TableName() string { return "site_groups" }
+
+type site0007 struct {
+	GroupID *uint `gorm:"index"`
+}
+
+func (site0007) TableName() string { return "sites" }
+
+// Tags (muitos para muitos) e grupos nomeados de sites
+var tagsGroups = Migration{
+	Version: 7,
+	Name:    "tags_groups",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&tag0007{}, &siteTag0007{}, &group0007{}); err != nil {
+			return err
+		}
+		if err := tx.Migrator().AddColumn(&site0007{}, "GroupID"); err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&site0007{}, "GroupID")
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&site0007{}, "GroupID") {
+			if err := tx.Migrator().DropIndex(&site0007{}, "GroupID"); err != nil {
+				return err
+			}
+		}
+		if err := tx.Migrator().DropColumn(&site0007{}, "GroupID"); err != nil {
+			return err
+		}
+		if err := restoreIndexes(tx, &site0001{}, "DeletedAt"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable("site_groups", "site_tags", "tags")
+	},
+}
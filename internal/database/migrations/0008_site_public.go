@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type site0008 struct {
+	Public bool `gorm:"not null;default:false"`
+}
+
+func (site0008) TableName() string { return "sites" }
+
+// Sites publicados na página de status pública
+var sitePublic = Migration{
+	Version: 8,
+	Name:    "site_public",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&site0008{}, "Public")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&site0008{}, "Public"); err != nil {
+			return err
+		}
+		if err := restoreIndexes(tx, &site0001{}, "DeletedAt"); err != nil {
+			return err
+		}
+		return restoreIndexes(tx, &site0007{}, "GroupID")
+	},
+}
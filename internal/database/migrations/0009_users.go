@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0009 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Username     string `gorm:"not null;uniqueIndex"`
+	PasswordHash string `gorm:"not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (user0009) TableName() string { return "users" }
+
+type session0009 struct {
+	TokenHash string    `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+func (session0009) TableName() string { return "sessions" }
+
+type apiKey0009 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	KeyHash    string `gorm:"not null;uniqueIndex"`
+	Scopes     string `gorm:"not null"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (apiKey0009) TableName() string { return "api_keys" }
+
+// Usuários, sessões de login e chaves de API
+var users = Migration{
+	Version: 9,
+	Name:    "users",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0009{}, &session0009{}, &apiKey0009{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&apiKey0009{}, &session0009{}, &user0009{})
+	},
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type roleBinding0010 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	GroupID   *uint  `gorm:"index"`
+	Role      string `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (roleBinding0010) TableName() string { return "role_bindings" }
+
+type incident0010 struct {
+	AcknowledgedAt *time.Time
+	AcknowledgedBy string
+}
+
+func (incident0010) TableName() string { return "incidents" }
+
+// Papéis por grupo de sites e reconhecimento de incidentes. Os usuários já existentes
+// viram admins globais, para que ninguém perca o acesso com a atualização
+var roles = Migration{
+	Version: 10,
+	Name:    "roles",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&roleBinding0010{}); err != nil {
+			return err
+		}
+		if err := tx.Exec("INSERT INTO role_bindings (user_id, role, created_at) SELECT id, 'admin', ? FROM users", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		for _, column := range []string{"AcknowledgedAt", "AcknowledgedBy"} {
+			if err := tx.Migrator().AddColumn(&incident0010{}, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, column := range []string{"AcknowledgedAt", "AcknowledgedBy"} {
+			if err := tx.Migrator().DropColumn(&incident0010{}, column); err != nil {
+				return err
+			}
+		}
+		if err := restoreIndexes(tx, &incident0001{}, "SiteID"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&roleBinding0010{})
+	},
+}
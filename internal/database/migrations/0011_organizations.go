@@ -0,0 +1,166 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organization0011 struct {
+	ID                      uint   `gorm:"primaryKey"`
+	Name                    string `gorm:"not null"`
+	Slug                    string `gorm:"not null;uniqueIndex"`
+	MaxSites                int    `gorm:"not null;default:0"`
+	MinCheckIntervalSeconds int    `gorm:"not null;default:0"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+func (organization0011) TableName() string { return "organizations" }
+
+type site0011 struct {
+	OrganizationID       uint       `gorm:"not null;default:1;uniqueIndex:idx_sites_organization_url"`
+	URL                  string     `gorm:"not null;uniqueIndex:idx_sites_organization_url"`
+	CheckIntervalSeconds int        `gorm:"not null;default:0"`
+	DeletedAt            *time.Time `gorm:"index"`
+	GroupID              *uint      `gorm:"index"`
+}
+
+func (site0011) TableName() string { return "sites" }
+
+// URL única em todo o banco, como era antes
+type siteURL0011 struct {
+	URL string `gorm:"not null;unique"`
+}
+
+func (siteURL0011) TableName() string { return "sites" }
+
+type group0011 struct {
+	OrganizationID uint   `gorm:"not null;default:1;uniqueIndex:idx_site_groups_organization_name"`
+	Name           string `gorm:"not null;uniqueIndex:idx_site_groups_organization_name"`
+}
+
+func (group0011) TableName() string { return "site_groups" }
+
+type user0011 struct {
+	OrganizationID uint   `gorm:"not null;default:1;index"`
+	Username       string `gorm:"not null;uniqueIndex"`
+}
+
+func (user0011) TableName() string { return "users" }
+
+// Organizações (multi-tenant). Os dados existentes ficam na organização padrão (ID 1), e a
+// URL dos sites e o nome dos grupos passam a ser únicos dentro de cada organização
+var organizations = Migration{
+	Version: 11,
+	Name:    "organizations",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&organization0011{}); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := tx.Create(&organization0011{ID: 1, Name: "Default", Slug: "default", CreatedAt: now, UpdatedAt: now}).Error; err != nil {
+			return err
+		}
+
+		if err := dropUniqueURL(tx); err != nil {
+			return err
+		}
+		for _, column := range []string{"OrganizationID", "CheckIntervalSeconds"} {
+			if err := tx.Migrator().AddColumn(&site0011{}, column); err != nil {
+				return err
+			}
+		}
+		if err := tx.Migrator().CreateIndex(&site0011{}, "idx_sites_organization_url"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().DropIndex("site_groups", "idx_site_groups_name"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().AddColumn(&group0011{}, "OrganizationID"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().CreateIndex(&group0011{}, "idx_site_groups_organization_name"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().AddColumn(&user0011{}, "OrganizationID"); err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&user0011{}, "OrganizationID")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&user0011{}, "OrganizationID"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(&user0011{}, "OrganizationID"); err != nil {
+			return err
+		}
+		// No SQLite, remover a coluna recria a tabela sem os índices
+		if !tx.Migrator().HasIndex(&user0011{}, "Username") {
+			if err := tx.Migrator().CreateIndex(&user0011{}, "Username"); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Migrator().DropIndex(&group0011{}, "idx_site_groups_organization_name"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(&group0011{}, "OrganizationID"); err != nil {
+			return err
+		}
+		if err := tx.Exec("CREATE UNIQUE INDEX idx_site_groups_name ON site_groups (name)").Error; err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().DropIndex(&site0011{}, "idx_sites_organization_url"); err != nil {
+			return err
+		}
+		for _, column := range []string{"CheckIntervalSeconds", "OrganizationID"} {
+			if err := tx.Migrator().DropColumn(&site0011{}, column); err != nil {
+				return err
+			}
+		}
+		if err := restoreUniqueURL(tx); err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropTable(&organization0011{})
+	},
+}
+
+// Remover a restrição UNIQUE da coluna url. No SQLite ela faz parte da definição da coluna
+// e só sai recriando a tabela, o que também apaga os índices, recriados em seguida
+func dropUniqueURL(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "sqlite" {
+		return tx.Exec("ALTER TABLE sites DROP CONSTRAINT IF EXISTS sites_url_key").Error
+	}
+
+	if err := tx.Migrator().AlterColumn(&site0011{}, "URL"); err != nil {
+		return err
+	}
+	for _, column := range []string{"DeletedAt", "GroupID"} {
+		if err := tx.Migrator().CreateIndex(&site0011{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Desfazer dropUniqueURL; falha se a mesma URL estiver em mais de uma organização
+func restoreUniqueURL(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "sqlite" {
+		return tx.Exec("ALTER TABLE sites ADD CONSTRAINT sites_url_key UNIQUE (url)").Error
+	}
+
+	if err := tx.Migrator().AlterColumn(&siteURL0011{}, "URL"); err != nil {
+		return err
+	}
+	for _, column := range []string{"DeletedAt", "GroupID"} {
+		if err := tx.Migrator().CreateIndex(&site0011{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type auditEntry0012 struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"not null;index:idx_audit_entries_organization_created,priority:1"`
+	UserID         uint   `gorm:"not null;index"`
+	Username       string `gorm:"not null"`
+	APIKeyID       *uint
+	Action         string `gorm:"not null;index"`
+	TargetType     string `gorm:"not null"`
+	TargetID       uint
+	TargetName     string
+	Changes        string `gorm:"not null"`
+	IP             string
+	CreatedAt      time.Time `gorm:"index:idx_audit_entries_organization_created,priority:2"`
+}
+
+func (auditEntry0012) TableName() string { return "audit_entries" }
+
+// Log de auditoria das alterações feitas pela API
+var auditLog = Migration{
+	Version: 12,
+	Name:    "audit_log",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&auditEntry0012{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&auditEntry0012{})
+	},
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// No máximo um incidente aberto por site. Checks simultâneos (o ticker e o "verificar
+// agora", ou várias réplicas) podiam abrir incidentes duplicados: fica o mais antigo
+var openIncidentUnique = Migration{
+	Version: 13,
+	Name:    "open_incident_unique",
+	Up: func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM incidents WHERE resolved_at IS NULL AND id NOT IN (
+			SELECT MIN(id) FROM incidents WHERE resolved_at IS NULL GROUP BY site_id)`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open_site ON incidents (site_id) WHERE resolved_at IS NULL").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec("DROP INDEX IF EXISTS idx_incidents_open_site").Error
+	},
+}
@@ -0,0 +1,267 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration versionada. Up e Down rodam dentro de uma transação
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Registro de uma migration aplicada (tabela schema_migrations)
+type SchemaMigration struct {
+	Version   int       `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Situação de cada migration conhecida ou aplicada
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Unknown   bool       `json:"unknown"` // Aplicada no banco, mas desconhecida por este binário
+}
+
+var ErrSchemaTooNew = errors.New("o banco de dados tem migrations mais novas que este binário")
+
+// No SQLite, remover ou alterar uma coluna recria a tabela sem os índices. Recriar os
+// índices do snapshot (pelo campo ou pelo nome) que deixaram de existir
+func restoreIndexes(tx *gorm.DB, model interface{}, indexes ...string) error {
+	for _, index := range indexes {
+		if tx.Migrator().HasIndex(model, index) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Chave do advisory lock do Postgres que serializa as migrations entre processos
+const lockKey int64 = 0x6d6f6e6974 // "monit"
+
+// Rodar fn com o advisory lock do Postgres, para que réplicas iniciando juntas não apliquem
+// as mesmas migrations em paralelo: a segunda espera e depois encontra o banco já migrado.
+// O lock fica em uma conexão dedicada e é liberado ao fechá-la. O SQLite já serializa as
+// escritas e roda fn direto
+func withLock(db *gorm.DB, fn func() error) error {
+	if db.Dialector.Name() != "postgres" {
+		return fn()
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("lock das migrations: %w", err)
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+
+	return fn()
+}
+
+// Versão mais recente conhecida por este binário
+func Latest() int {
+	return all[len(all)-1].Version
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&SchemaMigration{})
+}
+
+func applied(db *gorm.DB) ([]SchemaMigration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	err := db.Order("version").Find(&rows).Error
+	return rows, err
+}
+
+// Versão atual do banco (0 quando nada foi aplicado)
+func Current(db *gorm.DB) (int, error) {
+	rows, err := applied(db)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	return rows[len(rows)-1].Version, nil
+}
+
+// Recusar bancos com migrations que este binário não conhece
+func CheckCompatible(db *gorm.DB) error {
+	current, err := Current(db)
+	if err != nil {
+		return err
+	}
+	if current > Latest() {
+		return fmt.Errorf("%w (banco: %d, binário: %d)", ErrSchemaTooNew, current, Latest())
+	}
+	return nil
+}
+
+// Migrations ainda não aplicadas
+func Pending(db *gorm.DB) ([]Migration, error) {
+	rows, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		done[row.Version] = true
+	}
+
+	var pending []Migration
+	for _, migration := range all {
+		if !done[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Aplicar todas as migrations pendentes, em ordem. Retorna quantas foram aplicadas
+func Up(db *gorm.DB) (count int, err error) {
+	err = withLock(db, func() error {
+		count, err = up(db)
+		return err
+	})
+	return count, err
+}
+
+func up(db *gorm.DB) (int, error) {
+	if err := CheckCompatible(db); err != nil {
+		return 0, err
+	}
+
+	pending, err := Pending(db)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, migration := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return i, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return len(pending), nil
+}
+
+// Reverter as últimas steps migrations aplicadas. Retorna quantas foram revertidas
+func Down(db *gorm.DB, steps int) (reverted int, err error) {
+	err = withLock(db, func() error {
+		reverted, err = down(db, steps)
+		return err
+	})
+	return reverted, err
+}
+
+func down(db *gorm.DB, steps int) (int, error) {
+	if err := CheckCompatible(db); err != nil {
+		return 0, err
+	}
+
+	rows, err := applied(db)
+	if err != nil {
+		return 0, err
+	}
+
+	byVersion := make(map[int]Migration, len(all))
+	for _, migration := range all {
+		byVersion[migration.Version] = migration
+	}
+
+	reverted := 0
+	for i := len(rows) - 1; i >= 0 && reverted < steps; i-- {
+		migration, ok := byVersion[rows[i].Version]
+		if !ok {
+			// Aplicada por outro binário: este não sabe como revertê-la
+			return reverted, fmt.Errorf("rollback %04d_%s: migration desconhecida por este binário", rows[i].Version, rows[i].Name)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("rollback %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted++
+	}
+
+	return reverted, nil
+}
+
+// Situação de todas as migrations, ordenada pela versão
+func List(db *gorm.DB) ([]Status, error) {
+	rows, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		appliedAt[row.Version] = row
+	}
+
+	var statuses []Status
+	for _, migration := range all {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := appliedAt[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &row.AppliedAt
+			delete(appliedAt, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Versões aplicadas por um binário mais novo
+	for _, row := range rows {
+		if _, ok := appliedAt[row.Version]; ok {
+			row := row
+			statuses = append(statuses, Status{
+				Version:   row.Version,
+				Name:      row.Name,
+				Applied:   true,
+				AppliedAt: &row.AppliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+
+	return statuses, nil
+}
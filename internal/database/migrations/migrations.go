@@ -0,0 +1,19 @@
+package migrations
+
+// Todas as migrations, em ordem de versão. Novas migrations entram no fim da lista
+// e nunca devem ser alteradas depois de publicadas.
+var all = []Migration{
+	initialSchema,
+	logRollups,
+	rollupDurations,
+	siteStates,
+	siteVersion,
+	logKeysetIndex,
+	tagsGroups,
+	sitePublic,
+	users,
+	roles,
+	organizations,
+	auditLog,
+	openIncidentUnique,
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/sitefile"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Registro alterado por uma ação auditada
+type auditTarget struct {
+	kind string
+	id   uint
+	name string
+}
+
+func siteTarget(site models.Site) auditTarget {
+	return auditTarget{kind: models.AuditTargetSite, id: site.ID, name: site.Name}
+}
+
+func groupTarget(group models.Group) auditTarget {
+	return auditTarget{kind: models.AuditTargetGroup, id: group.ID, name: group.Name}
+}
+
+func userTarget(user models.User) auditTarget {
+	return auditTarget{kind: models.AuditTargetUser, id: user.ID, name: user.Username}
+}
+
+// Campos de cada registro comparados no log de auditoria. Segredos (senhas e chaves)
+// nunca entram, apenas o fato de terem sido alterados
+func siteAudit(site models.Site) models.SiteRequest {
+	return sitefile.Request(site)
+}
+
+func groupAudit(group models.Group) gin.H {
+	return gin.H{"name": group.Name, "description": group.Description}
+}
+
+func userAudit(user models.User) gin.H {
+	return gin.H{"username": user.Username}
+}
+
+func keyAudit(key models.APIKey) gin.H {
+	return gin.H{"name": key.Name, "prefix": key.Prefix, "scopes": key.ScopeList()}
+}
+
+// Papéis como "admin" (global) e "operator@3" (no grupo 3), em ordem
+func rolesAudit(roles []models.RoleBinding) gin.H {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.GroupID == nil {
+			names = append(names, role.Role)
+		} else {
+			names = append(names, fmt.Sprintf("%s@%d", role.Role, *role.GroupID))
+		}
+	}
+	sort.Strings(names)
+	return gin.H{"roles": names}
+}
+
+// Registrar uma alteração feita pela requisição, com os campos que mudaram entre before e
+// after (nil quando o registro não existia ou deixou de existir). A alteração já foi salva:
+// uma falha aqui é apenas logada, sem mudar a resposta
+func (h *Handler) audit(c *gin.Context, action string, target auditTarget, before, after interface{}) {
+	p, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		log.Printf("⚠️  Erro ao comparar %s %d para a auditoria: %v", target.kind, target.id, err)
+		changes = map[string]models.AuditChange{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		data = []byte("{}")
+	}
+
+	entry := models.AuditEntry{
+		OrganizationID: p.User.OrganizationID,
+		UserID:         p.User.ID,
+		Username:       p.User.Username,
+		Action:         action,
+		TargetType:     target.kind,
+		TargetID:       target.id,
+		TargetName:     target.name,
+		Changes:        string(data),
+		IP:             c.ClientIP(),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if p.APIKey != nil {
+		keyID := p.APIKey.ID
+		entry.APIKeyID = &keyID
+	}
+
+	if err := h.store.CreateAuditEntry(&entry); err != nil {
+		log.Printf("⚠️  Erro ao registrar %s de %s %d na auditoria: %v", action, target.kind, target.id, err)
+	}
+}
+
+// Registrar uma verificação manual ("verificar agora"), feita fora deste pacote, com o
+// resultado obtido
+func (h *Handler) AuditSiteCheck(c *gin.Context, result models.MonitorLog) {
+	site, err := h.store.GetSite(result.SiteID)
+	if err != nil {
+		log.Printf("⚠️  Erro ao buscar site %d para a auditoria: %v", result.SiteID, err)
+		return
+	}
+	h.audit(c, models.AuditSiteCheck, siteTarget(*site), nil,
+		gin.H{"status": result.Status, "status_code": result.StatusCode, "error_message": result.ErrorMessage})
+}
+
+// Campos com valores diferentes entre os dois registros, comparados pelo JSON de cada um
+func auditChanges(before, after interface{}) (map[string]models.AuditChange, error) {
+	from, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.AuditChange)
+	for field, value := range from {
+		if !reflect.DeepEqual(value, to[field]) {
+			changes[field] = models.AuditChange{Before: value, After: to[field]}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok && value != nil {
+			changes[field] = models.AuditChange{After: value}
+		}
+	}
+	return changes, nil
+}
+
+func auditFields(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// Uma linha da exportação da auditoria
+type auditRecord struct {
+	ID         uint                          `json:"id"`
+	CreatedAt  time.Time                     `json:"created_at"`
+	UserID     uint                          `json:"user_id"`
+	Username   string                        `json:"username"`
+	APIKeyID   *uint                         `json:"api_key_id"`
+	IP         string                        `json:"ip"`
+	Action     string                        `json:"action"`
+	TargetType string                        `json:"target_type"`
+	TargetID   uint                          `json:"target_id"`
+	TargetName string                        `json:"target_name"`
+	Changes    map[string]models.AuditChange `json:"changes"`
+}
+
+var auditColumns = []string{
+	"id", "created_at", "user_id", "username", "api_key_id", "ip",
+	"action", "target_type", "target_id", "target_name", "changes",
+}
+
+func (r auditRecord) csv() []string {
+	keyID := ""
+	if r.APIKeyID != nil {
+		keyID = strconv.FormatUint(uint64(*r.APIKeyID), 10)
+	}
+	changes, _ := json.Marshal(r.Changes)
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatUint(uint64(r.UserID), 10),
+		csvText(r.Username),
+		keyID,
+		r.IP,
+		r.Action,
+		r.TargetType,
+		strconv.FormatUint(uint64(r.TargetID), 10),
+		csvText(r.TargetName),
+		csvText(string(changes)),
+	}
+}
+
+// Alterações de um registro da auditoria; registros ilegíveis ficam sem alterações
+func entryChanges(entry models.AuditEntry) map[string]models.AuditChange {
+	changes := map[string]models.AuditChange{}
+	if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+		log.Printf("⚠️  Alterações ilegíveis no registro de auditoria %d: %v", entry.ID, err)
+	}
+	return changes
+}
+
+// GET /api/audit?actor=&action=&target_type=&target_id=&start_date=&end_date=&page=&limit=
+// Apenas admins globais veem a auditoria da organização
+func (h *Handler) GetAudit(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	filter, err := auditFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = tenant(c)
+	filter.Offset = (query.Page - 1) * query.Limit
+	filter.Limit = query.Limit
+
+	entries, total, err := h.store.ListAuditEntries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar auditoria"})
+		return
+	}
+
+	response := make([]models.AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, models.AuditEntryResponse{AuditEntry: entry, Changes: entryChanges(entry)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": response,
+		"total":   total,
+		"page":    query.Page,
+		"limit":   query.Limit,
+		"pages":   (total + int64(query.Limit) - 1) / int64(query.Limit),
+	})
+}
+
+// GET /api/audit/export?format=csv|ndjson&gzip=true, com os mesmos filtros de GET /api/audit,
+// do registro mais antigo ao mais recente
+func (h *Handler) ExportAudit(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, ok := newExportStream(c, "audit", auditColumns)
+	if !ok {
+		return
+	}
+
+	filter, err := auditFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = tenant(c)
+
+	err = h.store.StreamAuditEntries(filter, func(entry models.AuditEntry) error {
+		return stream.Write(auditRecord{
+			ID:         entry.ID,
+			CreatedAt:  entry.CreatedAt,
+			UserID:     entry.UserID,
+			Username:   entry.Username,
+			APIKeyID:   entry.APIKeyID,
+			IP:         entry.IP,
+			Action:     entry.Action,
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			TargetName: entry.TargetName,
+			Changes:    entryChanges(entry),
+		})
+	})
+	stream.Finish(err, "Erro ao exportar auditoria")
+}
+
+// Construir o filtro a partir da consulta (sem paginação); datas inválidas são erro
+func auditFilter(query models.AuditQuery) (storage.AuditFilter, error) {
+	filter := storage.AuditFilter{
+		Username:   strings.TrimSpace(query.Actor),
+		Action:     strings.TrimSpace(query.Action),
+		TargetType: strings.TrimSpace(query.TargetType),
+		TargetID:   query.TargetID,
+	}
+
+	if query.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", query.StartDate)
+		if err != nil {
+			return filter, fmt.Errorf("start_date inválida: %q (use AAAA-MM-DD)", query.StartDate)
+		}
+		filter.Since = startDate
+	}
+	if query.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", query.EndDate)
+		if err != nil {
+			return filter, fmt.Errorf("end_date inválida: %q (use AAAA-MM-DD)", query.EndDate)
+		}
+		filter.Until = endDate.Add(24 * time.Hour)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, errors.New("start_date deve ser anterior ou igual a end_date")
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/auth"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Cookie da sessão de login do dashboard
+const sessionCookie = "monitor_session"
+
+// Duração de uma sessão a partir do login
+const sessionTTL = 7 * 24 * time.Hour
+
+// Intervalo mínimo entre as atualizações de last_used_at de uma chave
+const keyTouchInterval = time.Minute
+
+type AuthConfig struct {
+	// Rotas de leitura abertas, sem login; quem lê sem login vê todos os sites
+	PublicRead bool
+}
+
+// AUTH_PUBLIC_READ=false exige login também nas rotas de leitura (padrão: leitura aberta)
+func AuthConfigFromEnv() AuthConfig {
+	return AuthConfig{PublicRead: os.Getenv("AUTH_PUBLIC_READ") != "false"}
+}
+
+// Quem fez a requisição: um usuário logado (sessão) ou uma chave de API
+type principal struct {
+	User   models.User
+	APIKey *models.APIKey // nil para sessões, que têm todos os escopos
+}
+
+func (p principal) can(scope string) bool {
+	return p.APIKey == nil || p.APIKey.HasScope(scope)
+}
+
+const principalKey = "principal"
+
+func currentPrincipal(c *gin.Context) (principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return principal{}, false
+	}
+	return value.(principal), true
+}
+
+// Identificar o cliente pelo header Authorization: Bearer <chave> ou pelo cookie de sessão.
+// Requisições sem credenciais seguem anônimas; uma chave inválida recebe 401
+func (h *Handler) Authenticate(c *gin.Context) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		key, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			abortUnauthorized(c, "Use Authorization: Bearer <chave de API>")
+			return
+		}
+
+		apiKey, err := h.store.FindAPIKey(auth.HashToken(strings.TrimSpace(key)))
+		if err != nil {
+			abortUnauthorized(c, "Chave de API inválida")
+			return
+		}
+
+		now := time.Now()
+		if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > keyTouchInterval {
+			if err := h.store.TouchAPIKey(apiKey.ID, now); err != nil {
+				log.Printf("⚠️  Erro ao registrar o uso da chave %d: %v", apiKey.ID, err)
+			}
+		}
+
+		c.Set(principalKey, principal{User: apiKey.User, APIKey: apiKey})
+		c.Next()
+		return
+	}
+
+	if token, err := c.Cookie(sessionCookie); err == nil && token != "" {
+		// Sessão expirada ou encerrada: o cookie é descartado e a requisição segue anônima
+		session, err := h.store.GetSession(auth.HashToken(token), time.Now())
+		if err == nil {
+			c.Set(principalKey, principal{User: session.User})
+		} else {
+			clearSessionCookie(c)
+		}
+	}
+
+	c.Next()
+}
+
+// Exigir autenticação: sempre nas alterações (escopo write) e, sem publicRead, também nas
+// leituras (escopo read). Deve vir depois de Authenticate
+func RequireAuth(publicRead bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := models.ScopeWrite
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = models.ScopeRead
+		}
+
+		p, ok := currentPrincipal(c)
+		switch {
+		case !ok && scope == models.ScopeRead && publicRead:
+		case !ok:
+			abortUnauthorized(c, "Autenticação necessária")
+			return
+		case !p.can(scope):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "A chave de API não tem o escopo " + scope})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Exigir login com usuário e senha: chaves de API não gerenciam usuários nem outras chaves
+func RequireSession(c *gin.Context) {
+	p, ok := currentPrincipal(c)
+	switch {
+	case !ok:
+		abortUnauthorized(c, "Autenticação necessária")
+	case p.APIKey != nil:
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Faça login com usuário e senha para esta operação"})
+	default:
+		c.Next()
+	}
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="website-monitoring"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
+
+// POST /api/auth/login
+func (h *Handler) Login(c *gin.Context) {
+	var request models.LoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.store.FindUserByUsername(request.Username)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		auth.CheckMissingPassword(request.Password)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário ou senha inválidos"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, request.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário ou senha inválidos"})
+		return
+	}
+
+	session, ok := h.startSession(c, user.ID)
+	if !ok {
+		return
+	}
+	// A requisição de login chega anônima: o usuário passa a ser o autor do registro
+	c.Set(principalKey, principal{User: *user})
+	h.audit(c, models.AuditUserLogin, userTarget(*user), nil, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user, "expires_at": session.ExpiresAt})
+}
+
+// Criar uma sessão para o usuário e enviar o cookie. Responde 500 e retorna false em caso
+// de erro
+func (h *Handler) startSession(c *gin.Context, userID uint) (*models.Session, bool) {
+	token, err := auth.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar sessão"})
+		return nil, false
+	}
+	session := models.Session{
+		TokenHash: auth.HashToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(sessionTTL),
+	}
+	if err := h.store.CreateSession(&session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar sessão"})
+		return nil, false
+	}
+
+	setSessionCookie(c, token, int(sessionTTL.Seconds()))
+	return &session, true
+}
+
+// POST /api/auth/logout
+func (h *Handler) Logout(c *gin.Context) {
+	if token, err := c.Cookie(sessionCookie); err == nil && token != "" {
+		if err := h.store.DeleteSession(auth.HashToken(token)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar sessão"})
+			return
+		}
+	}
+	// Sessões já expiradas não identificam o usuário e não são registradas
+	if p, ok := currentPrincipal(c); ok && p.APIKey == nil {
+		h.audit(c, models.AuditUserLogout, userTarget(p.User), nil, nil)
+	}
+
+	clearSessionCookie(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Sessão encerrada"})
+}
+
+// GET /api/auth/me
+func (h *Handler) Me(c *gin.Context) {
+	p, ok := currentPrincipal(c)
+	if !ok {
+		abortUnauthorized(c, "Autenticação necessária")
+		return
+	}
+
+	response := gin.H{"user": p.User, "method": "session"}
+	if p.APIKey != nil {
+		response["method"] = "api_key"
+		response["scopes"] = p.APIKey.ScopeList()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func setSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, token, maxAge, "/", "", secureRequest(c), true)
+}
+
+func clearSessionCookie(c *gin.Context) {
+	setSessionCookie(c, "", -1)
+}
+
+// HTTPS direto ou atrás de um proxy reverso
+func secureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
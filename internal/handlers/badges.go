@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/badge"
+	"github.com/luacarol/website-monitoring/internal/checker"
+	"github.com/luacarol/website-monitoring/internal/metrics"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Tempo de cache dos badges nos navegadores e proxies, e também no servidor: os badges não
+// exigem login, e cada janela (até 365 dias) é calculada no máximo uma vez nesse período
+const badgeMaxAge = 60 * time.Second
+
+var badgeColors = map[string]string{
+	string(checker.StatusUp):       badge.Green,
+	string(checker.StatusDegraded): badge.Yellow,
+	string(checker.StatusDown):     badge.Red,
+	models.StateUnknown:            badge.Grey,
+	models.StatePaused:             badge.Grey,
+}
+
+// GET /badge/:id/status.svg
+func (h *Handler) StatusBadge(c *gin.Context) {
+	site, ok := h.badgeSite(c, "status")
+	if !ok {
+		return
+	}
+
+	status := services.SiteStatus(*site, time.Now().UTC())
+	writeBadge(c, http.StatusOK, badgeLabel(c, "status"), status, badgeColors[status])
+}
+
+// GET /badge/:id/uptime.svg?window=30d (padrão 30d)
+func (h *Handler) UptimeBadge(c *gin.Context) {
+	site, ok := h.badgeSite(c, "uptime")
+	if !ok {
+		return
+	}
+
+	window := c.DefaultQuery("window", "30d")
+	d, err := windowDuration(window)
+	if err != nil {
+		writeBadge(c, http.StatusBadRequest, "uptime", "invalid window", badge.Grey)
+		return
+	}
+
+	value, err := h.badges.value(badgeKey{site: site.ID, kind: "uptime", window: d}, func() (badgeValue, error) {
+		now := time.Now()
+		report, err := h.stats.SiteUptime(*site, now.Add(-d), now)
+		if err != nil {
+			return badgeValue{}, err
+		}
+		return badgeValue{uptime: report.Uptime}, nil
+	})
+	if err != nil {
+		writeBadge(c, http.StatusInternalServerError, "uptime", "error", badge.Grey)
+		return
+	}
+
+	label := badgeLabel(c, "uptime "+window)
+	if value.uptime == nil {
+		writeBadge(c, http.StatusOK, label, "no data", badge.Grey)
+		return
+	}
+	writeBadge(c, http.StatusOK, label, formatUptime(*value.uptime), uptimeColor(*value.uptime))
+}
+
+// GET /badge/:id/response-time.svg?window=24h&stat=avg|p50|p90|p95|p99 (padrão 24h e avg)
+func (h *Handler) ResponseTimeBadge(c *gin.Context) {
+	site, ok := h.badgeSite(c, "response time")
+	if !ok {
+		return
+	}
+
+	window := c.DefaultQuery("window", "24h")
+	d, err := windowDuration(window)
+	if err != nil {
+		writeBadge(c, http.StatusBadRequest, "response time", "invalid window", badge.Grey)
+		return
+	}
+
+	stat := c.DefaultQuery("stat", "avg")
+	pick, ok := latencyStats[stat]
+	if !ok {
+		writeBadge(c, http.StatusBadRequest, "response time", "invalid stat", badge.Grey)
+		return
+	}
+
+	// Todas as estatísticas saem do mesmo cálculo: o cache é por janela, não por stat
+	cached, err := h.badges.value(badgeKey{site: site.ID, kind: "response-time", window: d}, func() (badgeValue, error) {
+		now := time.Now().UTC()
+		latency, count, err := h.stats.Latency(storage.Scope{SiteID: site.ID}, now.Add(-d), now)
+		return badgeValue{latency: latency, count: count}, err
+	})
+	if err != nil {
+		writeBadge(c, http.StatusInternalServerError, "response time", "error", badge.Grey)
+		return
+	}
+
+	label := badgeLabel(c, "response time")
+	if cached.count == 0 {
+		writeBadge(c, http.StatusOK, label, "no data", badge.Grey)
+		return
+	}
+
+	value := pick(cached.latency)
+	color := badge.Green
+	switch threshold := site.DegradedThresholdMs; {
+	case threshold > 0 && value >= threshold:
+		color = badge.Yellow
+	case threshold == 0 && value >= 1000:
+		color = badge.Yellow
+	}
+	message := fmt.Sprintf("%d ms", value)
+	if stat != "avg" {
+		message = stat + " " + message
+	}
+	writeBadge(c, http.StatusOK, label, message, color)
+}
+
+var latencyStats = map[string]func(metrics.Latency) int64{
+	"avg": func(l metrics.Latency) int64 { return l.Avg },
+	"p50": func(l metrics.Latency) int64 { return l.P50 },
+	"p90": func(l metrics.Latency) int64 { return l.P90 },
+	"p95": func(l metrics.Latency) int64 { return l.P95 },
+	"p99": func(l metrics.Latency) int64 { return l.P99 },
+}
+
+// Valores calculados de um badge. O SVG não é guardado porque o texto muda com ?label=
+type badgeKey struct {
+	site   uint
+	kind   string
+	window time.Duration
+}
+
+type badgeValue struct {
+	uptime  *float64
+	latency metrics.Latency
+	count   int64
+}
+
+// Valor já calculado de um badge. Como na página de status, o lock da entrada faz com que
+// requisições simultâneas esperem um único cálculo
+type cachedBadge struct {
+	mu      sync.Mutex
+	value   badgeValue
+	expires time.Time
+}
+
+type badgeCache struct {
+	mu      sync.Mutex
+	entries map[badgeKey]*cachedBadge
+}
+
+func newBadgeCache() *badgeCache {
+	return &badgeCache{entries: make(map[badgeKey]*cachedBadge)}
+}
+
+// Valor do cache ou, se expirado, o resultado de compute. Os erros não ficam no cache
+func (cache *badgeCache) value(key badgeKey, compute func() (badgeValue, error)) (badgeValue, error) {
+	entry := cache.entry(key)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if time.Now().Before(entry.expires) {
+		return entry.value, nil
+	}
+	value, err := compute()
+	if err != nil {
+		return badgeValue{}, err
+	}
+	entry.value, entry.expires = value, time.Now().Add(badgeMaxAge)
+	return value, nil
+}
+
+// Entrada da chave, criada se preciso. Qualquer janela até 365 dias é aceita, então as
+// entradas expiradas saem do mapa a cada chave nova para que ele não cresça sem limite
+func (cache *badgeCache) entry(key badgeKey) *cachedBadge {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		now := time.Now()
+		for k, e := range cache.entries {
+			if e.mu.TryLock() {
+				expired := now.After(e.expires)
+				e.mu.Unlock()
+				if expired {
+					delete(cache.entries, k)
+				}
+			}
+		}
+		entry = &cachedBadge{}
+		cache.entries[key] = entry
+	}
+	return entry
+}
+
+// Site público do badge. Sites inexistentes e internos respondem o mesmo badge 404,
+// para não revelar quais IDs existem
+func (h *Handler) badgeSite(c *gin.Context, label string) (*models.Site, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err == nil {
+		site, err := h.store.GetSite(uint(id))
+		if err == nil && site.Public {
+			if state, err := h.store.GetCurrentState(site.ID); err == nil {
+				site.State = state
+			}
+			return site, true
+		}
+	}
+
+	writeBadge(c, http.StatusNotFound, label, "not found", badge.Grey)
+	return nil, false
+}
+
+// Texto da esquerda: ?label= ou o padrão
+func badgeLabel(c *gin.Context, fallback string) string {
+	if label := c.Query("label"); label != "" && len(label) <= 50 {
+		return label
+	}
+	return fallback
+}
+
+func writeBadge(c *gin.Context, code int, label, message, color string) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(badgeMaxAge.Seconds())))
+	c.Data(code, "image/svg+xml; charset=utf-8", badge.Render(label, message, color))
+}
+
+// 99.95% com até duas casas, sem arredondar para 100% enquanto houver quedas
+func formatUptime(uptime float64) string {
+	if uptime < 100 && uptime > 99.99 {
+		uptime = 99.99
+	}
+	return strconv.FormatFloat(float64(int64(uptime*100))/100, 'f', -1, 64) + "%"
+}
+
+func uptimeColor(uptime float64) string {
+	switch {
+	case uptime >= 99.9:
+		return badge.Green
+	case uptime >= 99:
+		return badge.YellowGreen
+	case uptime >= 95:
+		return badge.Yellow
+	case uptime >= 90:
+		return badge.Orange
+	}
+	return badge.Red
+}
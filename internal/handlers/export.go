@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Enviar a resposta ao cliente a cada flushEvery registros
+const flushEvery = 1000
+
+// Uma linha de uma exportação: os campos em CSV ou o próprio valor em NDJSON
+type exportRecord interface {
+	csv() []string
+}
+
+// Uma linha da exportação de logs
+type logRecord struct {
+	ID           uint      `json:"id"`
+	SiteID       uint      `json:"site_id"`
+	SiteName     string    `json:"site_name"`
+	CheckedAt    time.Time `json:"checked_at"`
+	Status       string    `json:"status"`
+	IsOnline     bool      `json:"is_online"`
+	StatusCode   int       `json:"status_code"`
+	ResponseTime int64     `json:"response_time"`
+	CheckType    string    `json:"check_type"`
+	ErrorMessage string    `json:"error_message"`
+}
+
+var logColumns = []string{
+	"id", "site_id", "site_name", "checked_at", "status", "is_online",
+	"status_code", "response_time", "check_type", "error_message",
+}
+
+func (r logRecord) csv() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		strconv.FormatUint(uint64(r.SiteID), 10),
+		csvText(r.SiteName),
+		r.CheckedAt.UTC().Format(time.RFC3339Nano),
+		r.Status,
+		strconv.FormatBool(r.IsOnline),
+		strconv.Itoa(r.StatusCode),
+		strconv.FormatInt(r.ResponseTime, 10),
+		r.CheckType,
+		csvText(r.ErrorMessage),
+	}
+}
+
+// Texto livre em uma célula CSV. Planilhas interpretam células que começam com = + - @,
+// tab ou CR como fórmula; o apóstrofo na frente faz a célula ser lida como texto
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Escreve os registros em CSV ou NDJSON
+type recordEncoder interface {
+	Encode(record exportRecord) error
+	Flush() error
+}
+
+type csvRecordEncoder struct{ writer *csv.Writer }
+
+func (e csvRecordEncoder) Encode(record exportRecord) error { return e.writer.Write(record.csv()) }
+
+func (e csvRecordEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type ndjsonRecordEncoder struct{ encoder *json.Encoder }
+
+func (e ndjsonRecordEncoder) Encode(record exportRecord) error { return e.encoder.Encode(record) }
+func (e ndjsonRecordEncoder) Flush() error                     { return nil }
+
+// Resposta de uma exportação (?format=csv|ndjson&gzip=true) escrita à medida que os registros
+// chegam. A resposta só começa com o primeiro registro, para que um erro na consulta ainda
+// vire 500
+type exportStream struct {
+	c           *gin.Context
+	name        string // Base do nome do arquivo
+	format      string
+	contentType string
+	compress    bool
+	columns     []string
+
+	encoder recordEncoder
+	gz      *gzip.Writer
+	started bool
+	count   int
+}
+
+// Ler o formato da requisição; responde 400 e retorna false quando é inválido
+func newExportStream(c *gin.Context, name string, columns []string) (*exportStream, bool) {
+	s := &exportStream{c: c, name: name, columns: columns, format: c.DefaultQuery("format", "csv")}
+	switch s.format {
+	case "csv":
+		s.contentType = "text/csv; charset=utf-8"
+	case "ndjson":
+		s.contentType = "application/x-ndjson"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido (use csv ou ndjson)"})
+		return nil, false
+	}
+	s.compress = c.Query("gzip") == "true"
+	return s, true
+}
+
+func (s *exportStream) start() error {
+	s.started = true
+	filename := s.name + "." + s.format
+	contentType := s.contentType
+	if s.compress {
+		contentType = "application/gzip"
+		filename += ".gz"
+	}
+	s.c.Header("Content-Type", contentType)
+	s.c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	s.c.Status(http.StatusOK)
+
+	var out io.Writer = s.c.Writer
+	if s.compress {
+		s.gz = gzip.NewWriter(s.c.Writer)
+		out = s.gz
+	}
+	if s.format == "ndjson" {
+		s.encoder = ndjsonRecordEncoder{json.NewEncoder(out)}
+		return nil
+	}
+	writer := csv.NewWriter(out)
+	s.encoder = csvRecordEncoder{writer}
+	return writer.Write(s.columns)
+}
+
+func (s *exportStream) Write(record exportRecord) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if err := s.encoder.Encode(record); err != nil {
+		return err
+	}
+
+	s.count++
+	if s.count%flushEvery == 0 {
+		if err := s.encoder.Flush(); err != nil {
+			return err
+		}
+		if s.gz != nil {
+			if err := s.gz.Flush(); err != nil {
+				return err
+			}
+		}
+		s.c.Writer.Flush()
+	}
+	return nil
+}
+
+// Encerrar a resposta após a leitura. Com erro antes do primeiro registro responde 500 com
+// message; depois dele, o arquivo fica truncado (e o gzip, sem o final)
+func (s *exportStream) Finish(err error, message string) {
+	if err != nil && !s.started {
+		s.c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Exportação de %s interrompida após %d linhas: %v", s.name, s.count, err)
+		s.c.Abort()
+		return
+	}
+
+	if !s.started {
+		if err := s.start(); err != nil {
+			return
+		}
+	}
+	s.encoder.Flush()
+	if s.gz != nil {
+		s.gz.Close()
+	}
+}
+
+// GET /api/logs/export?format=csv|ndjson&gzip=true, com os mesmos filtros de GET /api/logs.
+// Os logs são lidos do banco com um cursor e escritos à medida que chegam, do mais antigo
+// ao mais recente
+func (h *Handler) ExportLogs(c *gin.Context) {
+	var query models.LogsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, ok := newExportStream(c, "logs", logColumns)
+	if !ok {
+		return
+	}
+
+	filter, err := logFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = tenant(c)
+	if filter.SiteIDs, err = h.scopedSiteIDs(c, query.Tag); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao exportar logs"})
+		return
+	}
+
+	names, err := h.siteNames(filter.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sites"})
+		return
+	}
+
+	err = h.store.StreamLogs(filter, func(entry models.MonitorLog) error {
+		return stream.Write(logRecord{
+			ID:           entry.ID,
+			SiteID:       entry.SiteID,
+			SiteName:     names[entry.SiteID],
+			CheckedAt:    entry.CheckedAt,
+			Status:       entry.Status,
+			IsOnline:     entry.IsOnline,
+			StatusCode:   entry.StatusCode,
+			ResponseTime: entry.ResponseTime,
+			CheckType:    entry.CheckType,
+			ErrorMessage: entry.ErrorMessage,
+		})
+	})
+	stream.Finish(err, "Erro ao exportar logs")
+}
+
+// Nomes de todos os sites da organização, inclusive os removidos
+func (h *Handler) siteNames(organizationID uint) (map[uint]string, error) {
+	sites, err := h.store.ListSites(storage.SiteFilter{OrganizationID: organizationID})
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := h.store.ListDeletedSites(organizationID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(sites)+len(deleted))
+	for _, site := range append(sites, deleted...) {
+		names[site.ID] = site.Name
+	}
+	return names, nil
+}
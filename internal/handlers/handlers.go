@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/luacarol/website-monitoring/internal/checker"
+	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Handler concentra as rotas da API e recebe o armazenamento por injeção
+type Handler struct {
+	store  storage.Store
+	stats  *services.StatsService
+	auth   AuthConfig
+	policy *checker.Policy // Destinos que os sites podem usar
+	badges *badgeCache
+}
+
+func New(store storage.Store, stats *services.StatsService, auth AuthConfig, policy *checker.Policy) *Handler {
+	return &Handler{store: store, stats: stats, auth: auth, policy: policy, badges: newBadgeCache()}
+}
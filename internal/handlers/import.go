@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/sitefile"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Limite do arquivo importado
+const maxImportSize = 10 << 20
+
+// Tratamento de URLs já cadastradas na importação
+const (
+	conflictSkip   = "skip"   // Manter o site existente (padrão)
+	conflictUpdate = "update" // Atualizar o site existente com os campos do arquivo
+	conflictFail   = "fail"   // Reportar a linha como falha
+)
+
+// POST /api/sites/import?format=json|csv|txt&on_conflict=skip|update|fail&dry_run=true
+// Cada linha é validada e salva separadamente; as falhas são reportadas sem interromper as demais
+func (h *Handler) ImportSites(c *gin.Context) {
+	format, ok := requestFormat(c)
+	if !ok {
+		return
+	}
+
+	onConflict := c.DefaultQuery("on_conflict", conflictSkip)
+	switch onConflict {
+	case conflictSkip, conflictUpdate, conflictFail:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "on_conflict deve ser skip, update ou fail"})
+		return
+	}
+
+	rows, err := sitefile.Decode(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Cada linha exige admin no grupo do site, conferido linha a linha
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return
+	}
+
+	// Os sites criados contam na cota, inclusive na simulação
+	organization, ok := h.currentOrganization(c)
+	if !ok {
+		return
+	}
+	quota := siteQuota{organization: *organization}
+	if quota.sites, err = h.store.CountSites(organization.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar sites"})
+		return
+	}
+
+	result := models.ImportResult{DryRun: c.Query("dry_run") == "true", Rows: []models.ImportRow{}}
+	seen := make(map[string]int)
+
+	for _, row := range rows {
+		report := models.ImportRow{Line: row.Line, URL: row.Request.URL}
+
+		if first, ok := seen[row.Request.URL]; ok && row.Request.URL != "" {
+			report.Status = models.ImportFailed
+			report.Error = fmt.Sprintf("URL repetida no arquivo (linha %d)", first)
+			result.Add(report)
+			continue
+		}
+		seen[row.Request.URL] = row.Line
+
+		h.importRow(c, a, &quota, row, onConflict, result.DryRun, &report)
+		result.Add(report)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Cota de sites da organização durante a importação
+type siteQuota struct {
+	organization models.Organization
+	sites        int64 // Sites atuais mais os criados pelas linhas anteriores
+}
+
+// Validar e salvar uma linha, preenchendo o resultado em report
+func (h *Handler) importRow(c *gin.Context, a access, quota *siteQuota, row sitefile.Row, onConflict string, dryRun bool, report *models.ImportRow) {
+	fail := func(message string) {
+		report.Status = models.ImportFailed
+		report.Error = message
+	}
+
+	if row.Err != nil {
+		fail(row.Err.Error())
+		return
+	}
+
+	organizationID := quota.organization.ID
+	existing, err := h.store.FindSiteByURL(organizationID, row.Request.URL)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		existing = nil
+	case err != nil:
+		fail("Erro ao buscar site")
+		return
+	}
+
+	request := row.Request
+	if existing != nil && onConflict == conflictUpdate {
+		request = row.Merge(*existing)
+	}
+	if request.Name == "" {
+		request.Name = sitefile.NameForURL(request.URL)
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		fail(err.Error())
+		return
+	}
+	if err := h.checkTagsAndGroup(organizationID, &request.Tags, request.GroupID); err != nil {
+		fail(err.Error())
+		return
+	}
+	if !a.allows(request.GroupID, models.RoleAdmin) {
+		fail("Permissão insuficiente: requer o papel admin no grupo do site")
+		return
+	}
+
+	if existing != nil && !a.allows(existing.GroupID, models.RoleAdmin) {
+		fail("Permissão insuficiente: requer o papel admin no grupo do site existente")
+		return
+	}
+
+	if existing != nil {
+		report.SiteID = existing.ID
+		switch {
+		case existing.DeletedAt.Valid:
+			fail("Já existe um site removido com esta URL; restaure-o antes de importar")
+			return
+		case onConflict == conflictSkip:
+			report.Status = models.ImportSkipped
+			return
+		case onConflict == conflictFail:
+			fail("Já existe um site com esta URL")
+			return
+		}
+	}
+
+	site := models.Site{Version: 1, OrganizationID: organizationID}
+	report.Status = models.ImportCreated
+	var before interface{}
+	if existing != nil {
+		before = siteAudit(*existing)
+		site = *existing
+		report.Status = models.ImportUpdated
+	} else if quota.organization.SiteQuotaReached(quota.sites) {
+		fail(siteQuotaError(quota.organization))
+		return
+	}
+	applySiteRequest(&site, request)
+
+	if err := h.checkTarget(site); err != nil {
+		fail(err.Error())
+		return
+	}
+	if err := checkInterval(quota.organization, &site); err != nil {
+		fail(err.Error())
+		return
+	}
+	if dryRun {
+		if existing == nil {
+			quota.sites++
+		}
+		return
+	}
+
+	if existing != nil {
+		err = h.store.UpdateSite(&site)
+	} else {
+		err = h.store.CreateSite(&site)
+	}
+	switch {
+	case errors.Is(err, storage.ErrDuplicate):
+		fail("Já existe um site com esta URL")
+	case errors.Is(err, storage.ErrConflict):
+		fail("O site foi alterado por outra operação durante a importação")
+	case err != nil:
+		fail("Erro ao salvar site")
+	default:
+		report.SiteID = site.ID
+		if existing == nil {
+			quota.sites++
+		}
+		h.audit(c, models.AuditSiteImport, siteTarget(site), before, siteAudit(site))
+	}
+}
+
+// GET /api/sites/export?format=json|csv|txt
+func (h *Handler) ExportSites(c *gin.Context) {
+	format, err := sitefile.ParseFormat(c.DefaultQuery("format", string(sitefile.FormatJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sites, err := h.store.ListSites(storage.SiteFilter{OrganizationID: tenant(c)})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sites"})
+		return
+	}
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return
+	}
+	visible := sites[:0]
+	for _, site := range sites {
+		if a.allows(site.GroupID, models.RoleViewer) {
+			visible = append(visible, site)
+		}
+	}
+	sites = visible
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="sites.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+	if err := sitefile.Encode(c.Writer, format, sites); err != nil {
+		c.Error(err)
+	}
+}
+
+// Formato do corpo: ?format= ou Content-Type; responde 400 e retorna false quando não reconhecido
+func requestFormat(c *gin.Context) (sitefile.Format, bool) {
+	if name := c.Query("format"); name != "" {
+		format, err := sitefile.ParseFormat(name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", false
+		}
+		return format, true
+	}
+
+	if format := sitefile.FormatForContentType(c.ContentType()); format != "" {
+		return format, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o formato em ?format= (json, csv ou txt) ou no Content-Type"})
+	return "", false
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// GET /api/incidents
+func (h *Handler) GetIncidents(c *gin.Context) {
+	var query models.IncidentsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Valores padrão
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+
+	visible, ok := h.visibleSiteIDs(c)
+	if !ok {
+		return
+	}
+
+	filter := storage.IncidentFilter{
+		OrganizationID: tenant(c),
+		SiteID:         query.SiteID,
+		SiteIDs:        visible,
+		Limit:          query.Limit,
+	}
+
+	switch query.Status {
+	case "", "all":
+	case "open":
+		open := true
+		filter.Open = &open
+	case "resolved":
+		open := false
+		filter.Open = &open
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+		return
+	}
+
+	incidents, err := h.store.ListIncidents(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar incidentes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"incidents": incidents,
+		"total":     len(incidents),
+	})
+}
+
+// POST /api/incidents/:id/ack: reconhecer o incidente aberto (papel operator no grupo do site)
+func (h *Handler) AcknowledgeIncident(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	incident, err := h.store.GetIncident(uint(id))
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Incidente não encontrado"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar incidente"})
+		return
+	}
+
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return
+	}
+	if incident.Site.OrganizationID != tenant(c) || !a.allows(incident.Site.GroupID, models.RoleViewer) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Incidente não encontrado"})
+		return
+	}
+	if !h.authorize(c, incident.Site.GroupID, models.RoleOperator) {
+		return
+	}
+
+	if !incident.IsOpen() {
+		c.JSON(http.StatusConflict, gin.H{"error": "O incidente já foi resolvido"})
+		return
+	}
+
+	p, _ := currentPrincipal(c)
+	now := time.Now().UTC()
+	if err := h.store.AcknowledgeIncident(incident.ID, p.User.Username, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao reconhecer incidente"})
+		return
+	}
+	h.audit(c, models.AuditIncidentAck, auditTarget{kind: models.AuditTargetIncident, id: incident.ID, name: incident.Site.Name},
+		gin.H{"acknowledged_by": incident.AcknowledgedBy}, gin.H{"acknowledged_by": p.User.Username})
+	incident.AcknowledgedAt = &now
+	incident.AcknowledgedBy = p.User.Username
+
+	c.JSON(http.StatusOK, gin.H{"message": "Incidente reconhecido", "incident": incident})
+}
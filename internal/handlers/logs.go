@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/luacarol/website-monitoring/internal/database"
 	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
 )
 
 // GET /api/logs
-func GetLogs(c *gin.Context) {
+// Paginação por página (page/limit, com total) ou por cursor (cursor/limit, sem total).
+// Ambas retornam next_cursor quando há mais logs
+func (h *Handler) GetLogs(c *gin.Context) {
 	var query models.LogsQuery
 
 	if err := c.ShouldBindQuery(&query); err != nil {
@@ -19,118 +26,338 @@ func GetLogs(c *gin.Context) {
 	}
 
 	// Valores padrão
-	if query.Page <= 0 {
-		query.Page = 1
-	}
 	if query.Limit <= 0 {
 		query.Limit = 50
 	}
-	if query.Status == "" {
-		query.Status = "all"
+
+	filter, err := logFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = tenant(c)
+	if filter.SiteIDs, err = h.scopedSiteIDs(c, query.Tag); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar logs"})
+		return
+	}
+
+	if query.Cursor != "" {
+		if query.Page > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Use page ou cursor, não os dois"})
+			return
+		}
+		cursor, err := decodeLogCursor(query.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.Before = &cursor
+		filter.Limit = query.Limit + 1
+
+		logs, err := h.store.PageLogs(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar logs"})
+			return
+		}
+
+		response := gin.H{"limit": query.Limit, "next_cursor": nil}
+		if len(logs) > query.Limit {
+			logs = logs[:query.Limit]
+			response["next_cursor"] = encodeLogCursor(logs[len(logs)-1])
+		}
+		response["logs"] = logs
+		c.JSON(http.StatusOK, response)
+		return
 	}
 
-	db := database.GetDB()
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	filter.Offset = (query.Page - 1) * query.Limit
+	filter.Limit = query.Limit
 
-	// Construir query
-	dbQuery := db.Model(&models.MonitorLog{}).Preload("Site")
+	// Buscar com paginação
+	logs, total, err := h.store.ListLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar logs"})
+		return
+	}
 
-	// Filtros
-	if query.SiteID != 0 {
-		dbQuery = dbQuery.Where("site_id = ?", query.SiteID)
+	var nextCursor interface{}
+	if len(logs) > 0 && int64(filter.Offset+len(logs)) < total {
+		nextCursor = encodeLogCursor(logs[len(logs)-1])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":        logs,
+		"total":       total,
+		"page":        query.Page,
+		"limit":       query.Limit,
+		"pages":       (total + int64(query.Limit) - 1) / int64(query.Limit),
+		"next_cursor": nextCursor,
+	})
+}
+
+// Construir o filtro a partir da consulta (sem paginação); valores inválidos são erro
+func logFilter(query models.LogsQuery) (storage.LogFilter, error) {
+	filter := storage.LogFilter{
+		SiteID:     query.SiteID,
+		MinLatency: query.MinResponseTime,
+		MaxLatency: query.MaxResponseTime,
+		ErrorText:  strings.TrimSpace(query.Error),
+		CheckType:  query.CheckType,
 	}
 
 	if query.StartDate != "" {
 		startDate, err := time.Parse("2006-01-02", query.StartDate)
-		if err == nil {
-			dbQuery = dbQuery.Where("checked_at >= ?", startDate)
+		if err != nil {
+			return filter, fmt.Errorf("start_date inválida: %q (use AAAA-MM-DD)", query.StartDate)
 		}
+		filter.Since = startDate
 	}
 
 	if query.EndDate != "" {
 		endDate, err := time.Parse("2006-01-02", query.EndDate)
-		if err == nil {
-			dbQuery = dbQuery.Where("checked_at <= ?", endDate.Add(24*time.Hour))
+		if err != nil {
+			return filter, fmt.Errorf("end_date inválida: %q (use AAAA-MM-DD)", query.EndDate)
 		}
+		filter.Until = endDate.Add(24 * time.Hour)
 	}
 
-	if query.Status == "online" {
-		dbQuery = dbQuery.Where("is_online = ?", true)
-	} else if query.Status == "offline" {
-		dbQuery = dbQuery.Where("is_online = ?", false)
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, errors.New("start_date deve ser anterior ou igual a end_date")
 	}
 
-	// Contar total
-	var total int64
-	dbQuery.Count(&total)
+	switch query.Status {
+	case "", "all":
+	case "online":
+		online := true
+		filter.Online = &online
+	case "offline":
+		online := false
+		filter.Online = &online
+	default:
+		return filter, fmt.Errorf("status inválido: %q (use online, offline ou all)", query.Status)
+	}
 
-	// Buscar com paginação
-	var logs []models.MonitorLog
-	offset := (query.Page - 1) * query.Limit
+	if query.StatusCode != "" {
+		ranges, err := parseStatusCodes(query.StatusCode)
+		if err != nil {
+			return filter, err
+		}
+		filter.StatusCodes = ranges
+	}
 
-	if err := dbQuery.Order("checked_at desc").Limit(query.Limit).Offset(offset).Find(&logs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar logs"})
-		return
+	if filter.MinLatency != nil && filter.MaxLatency != nil && *filter.MinLatency > *filter.MaxLatency {
+		return filter, errors.New("min_response_time deve ser menor ou igual a max_response_time")
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"logs":  logs,
-		"total": total,
-		"page":  query.Page,
-		"limit": query.Limit,
-		"pages": (total + int64(query.Limit) - 1) / int64(query.Limit),
-	})
+	return filter, nil
 }
 
-// GET /api/stats
-func GetStats(c *gin.Context) {
-	db := database.GetDB()
+// Interpretar "503", "500-599", "5xx" ou listas como "404,5xx". 0 são os checks sem resposta HTTP
+func parseStatusCodes(expr string) ([]storage.CodeRange, error) {
+	var ranges []storage.CodeRange
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		invalid := fmt.Errorf("status_code inválido: %q", part)
+
+		if len(part) == 3 && strings.HasSuffix(part, "xx") {
+			class, err := strconv.Atoi(part[:1])
+			if err != nil || class < 1 || class > 5 {
+				return nil, invalid
+			}
+			ranges = append(ranges, storage.CodeRange{Min: class * 100, Max: class*100 + 99})
+			continue
+		}
+
+		minText, maxText, isRange := strings.Cut(part, "-")
+		if !isRange {
+			maxText = minText
+		}
+		min, err := strconv.Atoi(strings.TrimSpace(minText))
+		if err != nil {
+			return nil, invalid
+		}
+		max, err := strconv.Atoi(strings.TrimSpace(maxText))
+		if err != nil {
+			return nil, invalid
+		}
+		if min < 0 || max > 599 || min > max {
+			return nil, invalid
+		}
 
-	var stats models.StatsResponse
+		ranges = append(ranges, storage.CodeRange{Min: min, Max: max})
+	}
 
-	// Total de sites ativos
-	var totalSites int64
-	db.Model(&models.Site{}).Where("active = ?", true).Count(&totalSites)
-	stats.TotalSites = int(totalSites)
+	return ranges, nil
+}
 
-	// Sites online/offline (baseado no último check)
-	// Query mais complexa para obter último status de cada site
-	var onlineCount int64
-	var offlineCount int64
+// Cursor opaco com a posição do último log da página
+func encodeLogCursor(log models.MonitorLog) string {
+	raw := strconv.FormatInt(log.CheckedAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(log.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
 
-	// Subquery para pegar o último log de cada site
-	subQuery := db.Table("monitor_logs").
-		Select("site_id, MAX(checked_at) as last_check").
-		Group("site_id")
+func decodeLogCursor(cursor string) (storage.LogCursor, error) {
+	invalid := errors.New("cursor inválido")
 
-	// Join para pegar o status do último check
-	db.Table("monitor_logs ml1").
-		Joins("JOIN (?) ml2 ON ml1.site_id = ml2.site_id AND ml1.checked_at = ml2.last_check", subQuery).
-		Joins("JOIN sites s ON ml1.site_id = s.id").
-		Where("s.active = ? AND ml1.is_online = ?", true, true).
-		Count(&onlineCount)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return storage.LogCursor{}, invalid
+	}
+	nanosText, idText, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return storage.LogCursor{}, invalid
+	}
+	nanos, err := strconv.ParseInt(nanosText, 10, 64)
+	if err != nil {
+		return storage.LogCursor{}, invalid
+	}
+	id, err := strconv.ParseUint(idText, 10, 64)
+	if err != nil {
+		return storage.LogCursor{}, invalid
+	}
 
-	db.Table("monitor_logs ml1").
-		Joins("JOIN (?) ml2 ON ml1.site_id = ml2.site_id AND ml1.checked_at = ml2.last_check", subQuery).
-		Joins("JOIN sites s ON ml1.site_id = s.id").
-		Where("s.active = ? AND ml1.is_online = ?", true, false).
-		Count(&offlineCount)
+	return storage.LogCursor{CheckedAt: time.Unix(0, nanos).UTC(), ID: uint(id)}, nil
+}
 
-	stats.OnlineSites = int(onlineCount)
-	stats.OfflineSites = int(offlineCount)
+// GET /api/stats
+func (h *Handler) GetStats(c *gin.Context) {
+	window, from, to, err := parseWindow(c, "24h")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	step, err := parseBucket(c, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Uptime geral (últimas 24h)
-	since := time.Now().Add(-24 * time.Hour)
-	var totalChecks int64
-	var onlineChecks int64
+	visible, ok := h.visibleSiteIDs(c)
+	if !ok {
+		return
+	}
 
-	db.Model(&models.MonitorLog{}).Where("checked_at >= ?", since).Count(&totalChecks)
-	db.Model(&models.MonitorLog{}).Where("checked_at >= ? AND is_online = ?", since, true).Count(&onlineChecks)
+	// Por tag, ou para quem vê apenas alguns grupos: números dos sites separados por grupo,
+	// sem checks, latência e série
+	organizationID := tenant(c)
+	if tag := strings.ToLower(strings.TrimSpace(c.Query("tag"))); tag != "" || visible != nil {
+		search := storage.SiteSearch{OrganizationID: organizationID, Tag: tag, SiteIDs: visible}
+		stats, err := h.stats.SitesOverview(search, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular estatísticas"})
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+		return
+	}
 
-	if totalChecks > 0 {
-		stats.OverallUptime = (float64(onlineChecks) / float64(totalChecks)) * 100
+	stats, err := h.stats.Overview(organizationID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular estatísticas"})
+		return
 	}
 
-	stats.LastUpdate = time.Now()
+	stats.WindowStats, err = h.stats.Window(storage.Scope{OrganizationID: organizationID}, from, to, step)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular estatísticas"})
+		return
+	}
+	stats.WindowStats.Window = window
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// GET /api/sites/:id/stats
+func (h *Handler) GetSiteStats(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	window, from, to, err := parseWindow(c, "24h")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	step, err := parseBucket(c, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return
+	}
+	if !h.viewable(c, *site) {
+		return
+	}
+
+	stats, err := h.stats.Window(storage.Scope{SiteID: uint(id)}, from, to, step)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular estatísticas"})
+		return
+	}
+	stats.Window = window
+
+	c.JSON(http.StatusOK, models.SiteStatsResponse{SiteID: uint(id), WindowStats: *stats})
+}
+
+// Assinatura das estatísticas do WebSocket: ?group=ID para um grupo, senão todos os sites
+// da organização que a requisição pode ver, sempre com uptime das últimas 24h. Os sites de cada grupo são
+// buscados novamente a cada envio. Responde e retorna false quando a assinatura não é permitida
+func (h *Handler) StatsFeed(c *gin.Context) (func() (*models.StatsResponse, error), bool) {
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return nil, false
+	}
+
+	organizationID := tenant(c)
+	if param := c.Query("group"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Grupo inválido"})
+			return nil, false
+		}
+		groupID := uint(id)
+		if _, err := h.organizationGroup(c, groupID); err != nil || !a.allows(&groupID, models.RoleViewer) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Grupo não encontrado"})
+			return nil, false
+		}
+		return func() (*models.StatsResponse, error) {
+			now := time.Now()
+			search := storage.SiteSearch{OrganizationID: organizationID, GroupID: groupID}
+			return h.stats.SitesOverview(search, now.Add(-24*time.Hour), now)
+		}, true
+	}
+
+	if models.RoleAllows(a.global, models.RoleViewer) {
+		return func() (*models.StatsResponse, error) {
+			now := time.Now()
+			return h.stats.Overview(organizationID, now.Add(-24*time.Hour), now)
+		}, true
+	}
+
+	groups := a.groupsAllowing(models.RoleViewer)
+	return func() (*models.StatsResponse, error) {
+		visible := []uint{}
+		if len(groups) > 0 {
+			ids, err := h.store.SiteIDsByGroup(groups...)
+			if err != nil {
+				return nil, err
+			}
+			visible = ids
+		}
+		now := time.Now()
+		search := storage.SiteSearch{OrganizationID: organizationID, SiteIDs: visible}
+		return h.stats.SitesOverview(search, now.Add(-24*time.Hour), now)
+	}, true
+}
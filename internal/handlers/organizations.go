@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/services"
+)
+
+// GET /api/organization: organização da requisição, com as cotas e o uso
+func (h *Handler) GetOrganization(c *gin.Context) {
+	organization, ok := h.currentOrganization(c)
+	if !ok {
+		return
+	}
+
+	sites, err := h.store.CountSites(organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar sites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OrganizationResponse{Organization: *organization, Sites: sites})
+}
+
+// Organização da requisição; responde 500 e retorna false em caso de erro
+func (h *Handler) currentOrganization(c *gin.Context) (*models.Organization, bool) {
+	organization, err := h.store.GetOrganization(tenant(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar organização"})
+		return nil, false
+	}
+	return organization, true
+}
+
+// Conferir a cota de sites antes de criar ou restaurar um; responde 403 e retorna false
+// quando ela já foi atingida
+func (h *Handler) checkSiteQuota(c *gin.Context, organization models.Organization) bool {
+	sites, err := h.store.CountSites(organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar sites"})
+		return false
+	}
+	if organization.SiteQuotaReached(sites) {
+		c.JSON(http.StatusForbidden, gin.H{"error": siteQuotaError(organization)})
+		return false
+	}
+	return true
+}
+
+func siteQuotaError(organization models.Organization) string {
+	return fmt.Sprintf("A organização atingiu o limite de %d sites", organization.MaxSites)
+}
+
+// Conferir o intervalo entre checks do site com a cota da organização. O intervalo padrão
+// (0) passa a ser o mínimo da organização quando ele é maior que o padrão
+func checkInterval(organization models.Organization, site *models.Site) error {
+	step := int(services.CheckInterval.Seconds())
+	if site.CheckIntervalSeconds%step != 0 {
+		return fmt.Errorf("check_interval_seconds deve ser múltiplo de %d", step)
+	}
+
+	minimum := organization.MinCheckIntervalSeconds
+	if site.CheckIntervalSeconds == 0 && minimum > step {
+		site.CheckIntervalSeconds = minimum
+	}
+	if site.CheckIntervalSeconds != 0 && site.CheckIntervalSeconds < minimum {
+		return fmt.Errorf("check_interval_seconds deve ser de pelo menos %d na organização", minimum)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Papéis efetivos de quem fez a requisição
+type access struct {
+	global string          // Papel em todos os sites
+	groups map[uint]string // Papel em cada grupo, além do global
+}
+
+// Papel nos sites do grupo (nil para os sites sem grupo)
+func (a access) role(groupID *uint) string {
+	if groupID == nil || *groupID == 0 {
+		return a.global
+	}
+	return models.MaxRole(a.global, a.groups[*groupID])
+}
+
+func (a access) allows(groupID *uint, required string) bool {
+	return models.RoleAllows(a.role(groupID), required)
+}
+
+// Grupos em que o papel inclui required, além do global
+func (a access) groupsAllowing(required string) []uint {
+	ids := []uint{}
+	for id, role := range a.groups {
+		if models.RoleAllows(role, required) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+const accessKey = "access"
+
+// Papéis do usuário da requisição, carregados uma vez por requisição. Chaves de API sem o
+// escopo write ficam limitadas a viewer; com leitura pública, todos são viewers globais
+func (h *Handler) access(c *gin.Context) (access, error) {
+	if cached, ok := c.Get(accessKey); ok {
+		return cached.(access), nil
+	}
+
+	result := access{groups: make(map[uint]string)}
+	if p, ok := currentPrincipal(c); ok {
+		bindings, err := h.store.ListRoles(p.User.ID)
+		if err != nil {
+			return access{}, err
+		}
+
+		readOnly := !p.can(models.ScopeWrite)
+		for _, binding := range bindings {
+			role := binding.Role
+			if readOnly {
+				role = models.RoleViewer
+			}
+			if binding.GroupID == nil {
+				result.global = models.MaxRole(result.global, role)
+			} else {
+				result.groups[*binding.GroupID] = models.MaxRole(result.groups[*binding.GroupID], role)
+			}
+		}
+	}
+	if h.auth.PublicRead {
+		result.global = models.MaxRole(result.global, models.RoleViewer)
+	}
+
+	c.Set(accessKey, result)
+	return result, nil
+}
+
+// Organização (tenant) da requisição: a do usuário ou, na leitura sem login, a padrão.
+// Todas as consultas da API ficam restritas a ela
+func tenant(c *gin.Context) uint {
+	if p, ok := currentPrincipal(c); ok {
+		return p.User.OrganizationID
+	}
+	return models.DefaultOrganizationID
+}
+
+// Conferir o papel nos sites do grupo; responde 403 e retorna false quando não basta
+func (h *Handler) authorize(c *gin.Context, groupID *uint, required string) bool {
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return false
+	}
+	if !a.allows(groupID, required) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Permissão insuficiente: requer o papel " + required})
+		return false
+	}
+	return true
+}
+
+// Exigir o papel admin em todos os sites (usuários, grupos e tags)
+func (h *Handler) authorizeGlobal(c *gin.Context) bool {
+	return h.authorize(c, nil, models.RoleAdmin)
+}
+
+// Conferir se o site é da organização e pode ser visto; responde 404, como se ele não
+// existisse, quando não
+func (h *Handler) viewable(c *gin.Context, site models.Site) bool {
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return false
+	}
+	if site.OrganizationID != tenant(c) || !a.allows(site.GroupID, models.RoleViewer) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return false
+	}
+	return true
+}
+
+// Sites que a requisição pode ver: nil para todos os da organização (viewer global), que
+// ainda precisam do filtro por organização. Responde 500 e retorna false em caso de erro
+func (h *Handler) visibleSiteIDs(c *gin.Context) ([]uint, bool) {
+	ids, err := h.visibleSites(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return nil, false
+	}
+	return ids, true
+}
+
+func (h *Handler) visibleSites(c *gin.Context) ([]uint, error) {
+	a, err := h.access(c)
+	if err != nil {
+		return nil, err
+	}
+	if models.RoleAllows(a.global, models.RoleViewer) {
+		return nil, nil
+	}
+
+	groups := a.groupsAllowing(models.RoleViewer)
+	if len(groups) == 0 {
+		return []uint{}, nil
+	}
+	return h.store.SiteIDsByGroup(groups...)
+}
+
+// Interseção de dois filtros de sites, em que nil não filtra
+func intersectIDs(a, b []uint) []uint {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	result := []uint{}
+	for _, id := range a {
+		for _, other := range b {
+			if id == other {
+				result = append(result, id)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Conferir o papel no site da requisição para rotas fora deste pacote (ex.: verificar agora).
+// Sites que não podem ser vistos respondem 404
+func (h *Handler) AuthorizeSite(c *gin.Context, siteID uint, required string) bool {
+	site, err := h.store.GetSite(siteID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return false
+	}
+	return h.viewable(c, *site) && h.authorize(c, site.GroupID, required)
+}
+
+// Buscar um site da lixeira e exigir admin no grupo dele antes de restaurar ou apagar.
+// Retorna nil, sem responder, quando o site não está na lixeira
+func (h *Handler) deletedSite(c *gin.Context, id uint) (*models.Site, bool) {
+	site, err := h.store.GetDeletedSite(tenant(c), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sites removidos"})
+		return nil, false
+	}
+	if !h.viewable(c, *site) || !h.authorize(c, site.GroupID, models.RoleAdmin) {
+		return nil, false
+	}
+	return site, true
+}
+
+// GET /api/users/:id/roles
+func (h *Handler) GetRoles(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	user, ok := h.userParam(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.store.ListRoles(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar papéis"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// PUT /api/users/:id/roles: substituir todos os papéis do usuário
+func (h *Handler) UpdateRoles(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	user, ok := h.userParam(c)
+	if !ok {
+		return
+	}
+
+	var request models.RolesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Um papel por grupo: o maior vence
+	merged := make(map[uint]string)
+	for _, role := range request.Roles {
+		var key uint
+		if role.GroupID != nil {
+			key = *role.GroupID
+		}
+		if key != 0 {
+			if group, err := h.store.GetGroup(key); err != nil || group.OrganizationID != user.OrganizationID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("grupo %d não encontrado", key)})
+				return
+			}
+		}
+		merged[key] = models.MaxRole(merged[key], role.Role)
+	}
+
+	// Quem edita os próprios papéis não pode tirar de si o admin global (e o acesso a esta rota)
+	if p, _ := currentPrincipal(c); p.User.ID == user.ID && !models.RoleAllows(merged[0], models.RoleAdmin) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Não é possível remover o próprio papel de admin global"})
+		return
+	}
+
+	roles := make([]models.RoleBinding, 0, len(merged))
+	for key, role := range merged {
+		binding := models.RoleBinding{Role: role}
+		if key != 0 {
+			groupID := key
+			binding.GroupID = &groupID
+		}
+		roles = append(roles, binding)
+	}
+	// Papel global primeiro, depois por grupo
+	sort.Slice(roles, func(i, j int) bool {
+		a, b := roles[i].GroupID, roles[j].GroupID
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return *a < *b
+	})
+
+	previous, err := h.store.ListRoles(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar papéis"})
+		return
+	}
+	if err := h.store.ReplaceRoles(user.ID, roles); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar papéis"})
+		return
+	}
+	h.audit(c, models.AuditUserRoles, userTarget(*user), rolesAudit(previous), rolesAudit(roles))
+	c.JSON(http.StatusOK, gin.H{"message": "Papéis atualizados com sucesso", "roles": roles})
+}
+
+// Usuário da rota, da mesma organização; responde e retorna false em caso de erro
+func (h *Handler) userParam(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return nil, false
+	}
+
+	user, err := h.store.GetUser(uint(id))
+	switch {
+	case errors.Is(err, storage.ErrNotFound) || (err == nil && user.OrganizationID != tenant(c)):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
+		return nil, false
+	}
+	return user, true
+}
@@ -1,49 +1,166 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/luacarol/website-monitoring/internal/database"
+	"github.com/luacarol/website-monitoring/internal/checker"
 	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage"
 )
 
 // GET /api/sites
-func GetSites(c *gin.Context) {
-	var sites []models.Site
-	db := database.GetDB()
+func (h *Handler) GetSites(c *gin.Context) {
+	var query models.SitesQuery
 
-	// Buscar sites com informações do último check
-	if err := db.Find(&sites).Error; err != nil {
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	visible, ok := h.visibleSiteIDs(c)
+	if !ok {
+		return
+	}
+
+	search := storage.SiteSearch{
+		OrganizationID: tenant(c),
+		SiteIDs:        visible,
+		Query:          query.Q,
+		Tag:            strings.ToLower(strings.TrimSpace(query.Tag)),
+		GroupID:        query.Group,
+		Sort:           query.Sort,
+	}
+	switch query.Sort {
+	case storage.SortByID, storage.SortByName, storage.SortByStatus, storage.SortByUptime, storage.SortByLatency:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordenação inválida"})
+		return
+	}
+	switch query.Order {
+	case "", "asc":
+	case "desc":
+		search.Desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordem inválida"})
+		return
+	}
+
+	// Sem page/limit, todos os sites (compatível com a listagem anterior)
+	paginated := query.Page > 0 || query.Limit > 0
+	if paginated {
+		if query.Page <= 0 {
+			query.Page = 1
+		}
+		if query.Limit <= 0 {
+			query.Limit = 50
+		}
+		search.Offset = (query.Page - 1) * query.Limit
+		search.Limit = query.Limit
+	}
+
+	// Estado atual e uptimes em cache vêm junto com os sites, em uma única consulta
+	sites, total, err := h.store.SearchSites(search)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sites"})
 		return
 	}
 
-	// Para cada site, buscar informações do último log
-	var sitesResponse []models.SiteResponse
+	now := time.Now().UTC()
+	sitesResponse := make([]models.SiteResponse, 0, len(sites))
 	for _, site := range sites {
-		var lastLog models.MonitorLog
-		db.Where("site_id = ?", site.ID).Order("checked_at desc").First(&lastLog)
-
 		siteResponse := models.SiteResponse{
-			Site:       site,
-			LastStatus: lastLog.StatusCode,
-			LastCheck:  lastLog.CheckedAt,
-			Uptime:     calculateUptime(site.ID), // Implementar função
+			Site:   site,
+			Status: services.SiteStatus(site, now),
+		}
+
+		if state := site.State; state != nil {
+			siteResponse.LastStatus = state.StatusCode
+			siteResponse.LastCheck = state.CheckedAt
+			siteResponse.LastResponseTime = state.ResponseTime
+			if state.Uptime24h != nil {
+				siteResponse.Uptime = *state.Uptime24h
+			}
+			siteResponse.Uptime7d = state.Uptime7d
+			siteResponse.Uptime30d = state.Uptime30d
+			siteResponse.Uptime90d = state.Uptime90d
 		}
+
 		sitesResponse = append(sitesResponse, siteResponse)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"sites": sitesResponse,
-		"total": len(sitesResponse),
+		"total": total,
+	}
+	if paginated {
+		response["page"] = query.Page
+		response["limit"] = query.Limit
+		response["pages"] = (total + int64(query.Limit) - 1) / int64(query.Limit)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /api/sites/:id
+func (h *Handler) GetSite(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return
+	}
+	if !h.viewable(c, *site) {
+		return
+	}
+
+	state, err := h.stats.State(*site)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar estado do site"})
+		return
+	}
+
+	incidents, err := h.store.ListIncidents(storage.IncidentFilter{SiteID: site.ID, Limit: 10})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar incidentes"})
+		return
+	}
+
+	history, err := h.stats.History(site.ID, 90)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico"})
+		return
+	}
+
+	c.Header("ETag", siteETag(*site))
+	c.JSON(http.StatusOK, models.SiteDetail{
+		Site:  *site,
+		State: *state,
+		Uptime: map[string]*float64{
+			"24h": h.windowUptime(*site, 24*time.Hour),
+			"7d":  h.windowUptime(*site, 7*24*time.Hour),
+			"30d": h.windowUptime(*site, 30*24*time.Hour),
+			"90d": h.windowUptime(*site, 90*24*time.Hour),
+		},
+		RecentIncidents: incidents,
+		History:         history,
 	})
 }
 
 // POST /api/sites
-func CreateSite(c *gin.Context) {
+func (h *Handler) CreateSite(c *gin.Context) {
 	var request models.SiteRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -51,18 +168,42 @@ func CreateSite(c *gin.Context) {
 		return
 	}
 
-	site := models.Site{
-		Name:   request.Name,
-		URL:    request.URL,
-		Active: true,
+	if err := h.checkTagsAndGroup(tenant(c), &request.Tags, request.GroupID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.authorize(c, request.GroupID, models.RoleAdmin) {
+		return
 	}
 
-	db := database.GetDB()
-	if err := db.Create(&site).Error; err != nil {
+	organization, ok := h.currentOrganization(c)
+	if !ok || !h.checkSiteQuota(c, *organization) {
+		return
+	}
+
+	site := models.Site{Version: 1, OrganizationID: organization.ID}
+	applySiteRequest(&site, request)
+
+	if err := h.checkTarget(site); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkInterval(*organization, &site); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.CreateSite(&site); err != nil {
+		if errors.Is(err, storage.ErrDuplicate) {
+			h.duplicateURL(c, site)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar site"})
 		return
 	}
+	h.audit(c, models.AuditSiteCreate, siteTarget(site), nil, siteAudit(site))
 
+	c.Header("ETag", siteETag(site))
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Site criado com sucesso",
 		"site":    site,
@@ -70,7 +211,7 @@ func CreateSite(c *gin.Context) {
 }
 
 // DELETE /api/sites/:id
-func DeleteSite(c *gin.Context) {
+func (h *Handler) DeleteSite(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -78,46 +219,431 @@ func DeleteSite(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-
 	// Verificar se site existe
-	var site models.Site
-	if err := db.First(&site, id).Error; err != nil {
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
 		return
 	}
+	if !h.viewable(c, *site) || !h.authorize(c, site.GroupID, models.RoleAdmin) {
+		return
+	}
 
-	// Soft delete
-	if err := db.Delete(&site).Error; err != nil {
+	// Soft delete: o site vai para a lixeira e pode ser restaurado
+	if err := h.store.DeleteSite(uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar site"})
 		return
 	}
+	h.audit(c, models.AuditSiteDelete, siteTarget(*site), siteAudit(*site), nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Site removido com sucesso"})
 }
 
-// PUT /api/sites/:id/toggle
-func ToggleSite(c *gin.Context) {
+// PUT /api/sites/:id
+func (h *Handler) UpdateSite(c *gin.Context) {
+	site, ok := h.siteForUpdate(c)
+	if !ok {
+		return
+	}
+
+	var request models.SiteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.checkTagsAndGroup(site.OrganizationID, &request.Tags, request.GroupID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := siteAudit(*site)
+	applySiteRequest(site, request)
+	h.saveSite(c, site, before)
+}
+
+// PATCH /api/sites/:id
+func (h *Handler) PatchSite(c *gin.Context) {
+	site, ok := h.siteForUpdate(c)
+	if !ok {
+		return
+	}
+
+	var patch models.SitePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.checkTagsAndGroup(site.OrganizationID, patch.Tags, patch.GroupID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := siteAudit(*site)
+	applySitePatch(site, patch)
+	h.saveSite(c, site, before)
+}
+
+// Buscar o site da rota e conferir o If-Match; responde e retorna false em caso de erro
+func (h *Handler) siteForUpdate(c *gin.Context) (*models.Site, bool) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return nil, false
+	}
+
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return nil, false
+	}
+	if !h.viewable(c, *site) || !h.authorize(c, site.GroupID, models.RoleAdmin) {
+		return nil, false
+	}
+
+	if !checkIfMatch(c, *site) {
+		return nil, false
+	}
+	return site, true
+}
+
+// Validar e salvar o site editado; mover o site exige admin também no grupo de destino.
+// before são os campos anteriores à edição, para a auditoria
+func (h *Handler) saveSite(c *gin.Context, site *models.Site, before models.SiteRequest) {
+	if !h.authorize(c, site.GroupID, models.RoleAdmin) {
+		return
+	}
+
+	if err := h.checkTarget(*site); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	organization, ok := h.currentOrganization(c)
+	if !ok {
+		return
+	}
+	if err := checkInterval(*organization, site); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.UpdateSite(site); err != nil {
+		h.updateError(c, *site, err)
+		return
+	}
+	h.audit(c, models.AuditSiteUpdate, siteTarget(*site), before, siteAudit(*site))
+
+	c.Header("ETag", siteETag(*site))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Site atualizado com sucesso",
+		"site":    site,
+	})
+}
+
+// Responder aos erros de UpdateSite
+func (h *Handler) updateError(c *gin.Context, site models.Site, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+	case errors.Is(err, storage.ErrDuplicate):
+		h.duplicateURL(c, site)
+	case errors.Is(err, storage.ErrConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "O site foi alterado por outra operação; recarregue e tente novamente"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar site"})
+	}
+}
+
+// 409 com o site da organização que já usa a URL, inclusive quando ele está removido
+func (h *Handler) duplicateURL(c *gin.Context, site models.Site) {
+	response := gin.H{"error": "Já existe um site com esta URL"}
+
+	if existing, err := h.store.FindSiteByURL(site.OrganizationID, site.URL); err == nil {
+		response["site_id"] = existing.ID
+		if existing.DeletedAt.Valid {
+			response["error"] = "Já existe um site removido com esta URL"
+			response["deleted"] = true
+		}
+	}
+
+	c.JSON(http.StatusConflict, response)
+}
+
+// ETag forte a partir da versão do site
+func siteETag(site models.Site) string {
+	return `"` + strconv.FormatUint(uint64(site.ID), 10) + "-" + strconv.Itoa(site.Version) + `"`
+}
+
+// Conferir o If-Match (opcional); responde 412 e retorna false quando não confere
+func checkIfMatch(c *gin.Context, site models.Site) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+
+	current := siteETag(site)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	c.Header("ETag", current)
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "O site foi alterado desde a última leitura (If-Match não confere)"})
+	return false
+}
+
+// Aplicar todos os campos editáveis (PUT e criação)
+func applySiteRequest(site *models.Site, request models.SiteRequest) {
+	site.Name = request.Name
+	site.URL = request.URL
+	site.Active = request.Active == nil || *request.Active
+	site.CheckType = request.CheckType
+	site.ExpectedStatus = request.ExpectedStatus
+	site.Keyword = request.Keyword
+	site.TimeoutSeconds = request.TimeoutSeconds
+	site.DegradedThresholdMs = request.DegradedThresholdMs
+	site.Public = request.Public
+	site.CheckIntervalSeconds = request.CheckIntervalSeconds
+	site.Tags = models.TagsFromNames(request.Tags)
+	site.GroupID = nil
+	site.Group = nil
+	if request.GroupID != nil && *request.GroupID != 0 {
+		site.GroupID = request.GroupID
+	}
+
+	if site.CheckType == "" {
+		site.CheckType = string(checker.TypeFor(site.URL))
+	}
+}
+
+// Aplicar apenas os campos informados (PATCH)
+func applySitePatch(site *models.Site, patch models.SitePatch) {
+	if patch.URL != nil {
+		// O tipo inferido da URL antiga acompanha a nova URL
+		if patch.CheckType == nil && site.CheckType == string(checker.TypeFor(site.URL)) {
+			site.CheckType = string(checker.TypeFor(*patch.URL))
+		}
+		site.URL = *patch.URL
+	}
+	if patch.Name != nil {
+		site.Name = *patch.Name
+	}
+	if patch.Active != nil {
+		site.Active = *patch.Active
+	}
+	if patch.CheckType != nil {
+		site.CheckType = *patch.CheckType
+	}
+	if patch.ExpectedStatus != nil {
+		site.ExpectedStatus = *patch.ExpectedStatus
+	}
+	if patch.Keyword != nil {
+		site.Keyword = *patch.Keyword
+	}
+	if patch.TimeoutSeconds != nil {
+		site.TimeoutSeconds = *patch.TimeoutSeconds
+	}
+	if patch.DegradedThresholdMs != nil {
+		site.DegradedThresholdMs = *patch.DegradedThresholdMs
+	}
+	if patch.Public != nil {
+		site.Public = *patch.Public
+	}
+	if patch.CheckIntervalSeconds != nil {
+		site.CheckIntervalSeconds = *patch.CheckIntervalSeconds
+	}
+	if patch.Tags != nil {
+		site.Tags = models.TagsFromNames(*patch.Tags)
+	}
+	if patch.GroupID != nil {
+		site.GroupID = nil
+		site.Group = nil
+		if *patch.GroupID != 0 {
+			site.GroupID = patch.GroupID
+		}
+	}
+}
+
+// Validar a configuração do check e conferir o alvo com a política de destinos. Os
+// endereços resolvidos são conferidos novamente a cada check
+func (h *Handler) checkTarget(site models.Site) error {
+	if err := site.CheckConfig().Validate(); err != nil {
+		return err
+	}
+	return h.policy.CheckTarget(site.URL)
+}
+
+// Normalizar as tags e conferir se o grupo existe na organização (group_id 0 ou nil: sem grupo)
+func (h *Handler) checkTagsAndGroup(organizationID uint, tags *[]string, groupID *uint) error {
+	if tags != nil {
+		normalized, err := models.NormalizeTags(*tags)
+		if err != nil {
+			return err
+		}
+		*tags = normalized
+	}
+
+	if groupID != nil && *groupID != 0 {
+		if group, err := h.store.GetGroup(*groupID); err != nil || group.OrganizationID != organizationID {
+			return fmt.Errorf("grupo %d não encontrado", *groupID)
+		}
+	}
+	return nil
+}
+
+// GET /api/sites/deleted
+func (h *Handler) GetDeletedSites(c *gin.Context) {
+	sites, err := h.store.ListDeletedSites(tenant(c), time.Time{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sites removidos"})
+		return
+	}
+	a, err := h.access(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar permissões"})
+		return
+	}
+
+	deleted := make([]models.DeletedSite, 0, len(sites))
+	for _, site := range sites {
+		if !a.allows(site.GroupID, models.RoleViewer) {
+			continue
+		}
+		deleted = append(deleted, models.DeletedSite{Site: site, DeletedAt: site.DeletedAt.Time})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sites": deleted,
+		"total": len(deleted),
+	})
+}
+
+// POST /api/sites/:id/restore?active=false para restaurar sem reativar
+func (h *Handler) RestoreSite(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
 		return
 	}
+	deleted, ok := h.deletedSite(c, uint(id))
+	if !ok {
+		return
+	}
+	if deleted == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado na lixeira"})
+		return
+	}
+	organization, ok := h.currentOrganization(c)
+	if !ok || !h.checkSiteQuota(c, *organization) {
+		return
+	}
 
-	db := database.GetDB()
-	var site models.Site
+	site, err := h.store.RestoreSite(uint(id))
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado na lixeira"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao restaurar site"})
+		return
+	}
 
-	if err := db.First(&site, id).Error; err != nil {
+	// Logs, incidentes e agregados nunca saíram do banco; basta reativar o monitoramento
+	if c.DefaultQuery("active", "true") != "false" && !site.Active {
+		site.Active = true
+		if err := h.store.UpdateSite(site); err != nil {
+			h.updateError(c, *site, err)
+			return
+		}
+	}
+	h.audit(c, models.AuditSiteRestore, siteTarget(*site), siteAudit(*deleted), siteAudit(*site))
+
+	c.Header("ETag", siteETag(*site))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Site restaurado com sucesso",
+		"site":    site,
+	})
+}
+
+// DELETE /api/sites/:id/purge
+func (h *Handler) PurgeSite(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Apenas sites na lixeira podem ser apagados definitivamente
+	site, err := h.store.GetSite(uint(id))
+	switch {
+	case err == nil:
+		if h.viewable(c, *site) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Remova o site antes de apagá-lo definitivamente"})
+		}
+		return
+	case !errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar site"})
+		return
+	}
+
+	// O store apaga qualquer site pelo ID: só seguir com um site da lixeira já autorizado
+	deleted, ok := h.deletedSite(c, uint(id))
+	if !ok {
+		return
+	}
+	if deleted == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado na lixeira"})
+		return
+	}
+
+	if err := h.store.PurgeSite(deleted.ID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao apagar site"})
+		return
+	}
+	h.audit(c, models.AuditSitePurge, siteTarget(*deleted), siteAudit(*deleted), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Site apagado definitivamente com seus logs, incidentes e agregados"})
+}
+
+// PUT /api/sites/:id/toggle
+func (h *Handler) ToggleSite(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
 		return
 	}
 
+	if !h.viewable(c, *site) || !h.authorize(c, site.GroupID, models.RoleAdmin) {
+		return
+	}
+
+	if !checkIfMatch(c, *site) {
+		return
+	}
+
+	before := siteAudit(*site)
 	site.Active = !site.Active
-	if err := db.Save(&site).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar site"})
+	if err := h.store.UpdateSite(site); err != nil {
+		h.updateError(c, *site, err)
 		return
 	}
+	h.audit(c, models.AuditSiteToggle, siteTarget(*site), before, siteAudit(*site))
+	c.Header("ETag", siteETag(*site))
 
 	status := "ativado"
 	if !site.Active {
@@ -130,21 +656,47 @@ func ToggleSite(c *gin.Context) {
 	})
 }
 
-// Função helper para calcular uptime
-func calculateUptime(siteID uint) float64 {
-	db := database.GetDB()
+// Uptime ponderado pelo tempo na janela até agora (nil sem dados ou em caso de erro)
+func (h *Handler) windowUptime(site models.Site, window time.Duration) *float64 {
+	now := time.Now()
 
-	var totalLogs int64
-	var onlineLogs int64
+	report, err := h.stats.SiteUptime(site, now.Add(-window), now)
+	if err != nil {
+		return nil
+	}
+	return report.Uptime
+}
 
-	// Contar logs dos últimos 24h
-	since := time.Now().Add(-24 * time.Hour)
-	db.Model(&models.MonitorLog{}).Where("site_id = ? AND checked_at >= ?", siteID, since).Count(&totalLogs)
-	db.Model(&models.MonitorLog{}).Where("site_id = ? AND checked_at >= ? AND is_online = ?", siteID, since, true).Count(&onlineLogs)
+// GET /api/sites/:id/uptime
+func (h *Handler) GetSiteUptime(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
 
-	if totalLogs == 0 {
-		return 0.0
+	window, from, to, err := parseWindow(c, "24h")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.store.GetSite(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Site não encontrado"})
+		return
+	}
+	if !h.viewable(c, *site) {
+		return
+	}
+
+	report, err := h.stats.SiteUptime(*site, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular uptime"})
+		return
 	}
+	report.Window = window
 
-	return (float64(onlineLogs) / float64(totalLogs)) * 100
+	c.JSON(http.StatusOK, report)
 }
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/checker"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/services"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+//go:embed templates/status.html
+var templateFiles embed.FS
+
+var statusTemplate = template.Must(template.New("status.html").Funcs(template.FuncMap{
+	"percent": func(value *float64) string {
+		if value == nil {
+			return "—"
+		}
+		return fmt.Sprintf("%.2f%%", *value)
+	},
+	"ago": func(since time.Time) string {
+		return formatDuration(time.Since(since))
+	},
+}).ParseFS(templateFiles, "templates/status.html"))
+
+// Dias exibidos na barra de uptime da página de status
+const statusPageDays = 90
+
+// Componente sem grupo na página de status
+const ungroupedComponent = "Other services"
+
+// Tempo de cache da página nos navegadores e proxies, e também no servidor: o histórico de
+// 90 dias de cada site é calculado no máximo uma vez nesse período por organização
+const statusPageMaxAge = 30 * time.Second
+
+// Página de status pública (sem autenticação), com os sites marcados como public. No
+// endereço padrão ficam os sites da organização padrão; os das demais, em <Path>/<slug>
+type StatusPageConfig struct {
+	Path  string // Vazio desativa a página
+	Title string
+}
+
+// STATUS_PAGE_PATH (padrão /status; vazio desativa) e STATUS_PAGE_TITLE
+func StatusPageConfigFromEnv() StatusPageConfig {
+	config := StatusPageConfig{Path: "/status", Title: "Service Status"}
+
+	if path, ok := os.LookupEnv("STATUS_PAGE_PATH"); ok {
+		config.Path = strings.TrimSuffix(strings.TrimSpace(path), "/")
+		if config.Path != "" && !strings.HasPrefix(config.Path, "/") {
+			config.Path = "/" + config.Path
+		}
+	}
+	if title := os.Getenv("STATUS_PAGE_TITLE"); title != "" {
+		config.Title = title
+	}
+
+	return config
+}
+
+// Situação geral exibida no topo da página
+const (
+	overallOperational = "operational"
+	overallDegraded    = "degraded"
+	overallPartial     = "partial"
+	overallMajor       = "major"
+)
+
+var overallText = map[string]string{
+	overallOperational: "All systems operational",
+	overallDegraded:    "Degraded performance",
+	overallPartial:     "Partial outage",
+	overallMajor:       "Major outage",
+}
+
+var statusText = map[string]string{
+	string(checker.StatusUp):       "Operational",
+	string(checker.StatusDegraded): "Degraded",
+	string(checker.StatusDown):     "Outage",
+	models.StateUnknown:            "No data",
+	models.StatePaused:             "Paused",
+}
+
+type statusPage struct {
+	Title       string
+	Overall     string
+	OverallText string
+	Components  []statusComponent
+	Incidents   []statusIncident
+	UpdatedAt   time.Time
+}
+
+// Sites de um grupo
+type statusComponent struct {
+	Name  string
+	Sites []statusSite
+}
+
+type statusSite struct {
+	Name       string
+	Status     string
+	StatusText string
+	Uptime     *float64 // Últimos 90 dias
+	History    []models.DailyStatus
+}
+
+// Incidente aberto, sem a causa (que pode expor detalhes internos)
+type statusIncident struct {
+	SiteName  string
+	StartedAt time.Time
+}
+
+// Página já renderizada de uma organização. O lock da entrada faz com que requisições
+// simultâneas esperem uma única renderização
+type renderedStatusPage struct {
+	mu      sync.Mutex
+	html    []byte
+	expires time.Time
+}
+
+type statusPageCache struct {
+	mu    sync.Mutex
+	pages map[uint]*renderedStatusPage
+}
+
+func (cache *statusPageCache) page(organizationID uint) *renderedStatusPage {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	page, ok := cache.pages[organizationID]
+	if !ok {
+		page = &renderedStatusPage{}
+		cache.pages[organizationID] = page
+	}
+	return page
+}
+
+// GET <STATUS_PAGE_PATH> e <STATUS_PAGE_PATH>/:org
+func (h *Handler) StatusPage(config StatusPageConfig) gin.HandlerFunc {
+	cache := &statusPageCache{pages: make(map[uint]*renderedStatusPage)}
+
+	return func(c *gin.Context) {
+		organizationID, title := uint(models.DefaultOrganizationID), config.Title
+		if slug := c.Param("org"); slug != "" {
+			organization, err := h.store.FindOrganizationBySlug(slug)
+			if errors.Is(err, storage.ErrNotFound) {
+				c.String(http.StatusNotFound, "Status page not found")
+				return
+			}
+			if err != nil {
+				log.Printf("❌ Erro ao buscar a organização %s: %v", slug, err)
+				c.String(http.StatusInternalServerError, "Status temporarily unavailable")
+				return
+			}
+			organizationID, title = organization.ID, organization.Name+" · "+config.Title
+		}
+
+		rendered := cache.page(organizationID)
+		rendered.mu.Lock()
+		if time.Now().After(rendered.expires) {
+			html, err := h.renderStatusPage(organizationID, title)
+			if err != nil {
+				rendered.mu.Unlock()
+				log.Printf("❌ Erro ao montar a página de status: %v", err)
+				c.String(http.StatusInternalServerError, "Status temporarily unavailable")
+				return
+			}
+			rendered.html, rendered.expires = html, time.Now().Add(statusPageMaxAge)
+		}
+		html := rendered.html
+		rendered.mu.Unlock()
+
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusPageMaxAge.Seconds())))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", html)
+	}
+}
+
+func (h *Handler) renderStatusPage(organizationID uint, title string) ([]byte, error) {
+	page, err := h.statusPage(organizationID, title)
+	if err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := statusTemplate.Execute(&html, page); err != nil {
+		return nil, err
+	}
+	return html.Bytes(), nil
+}
+
+func (h *Handler) statusPage(organizationID uint, title string) (*statusPage, error) {
+	now := time.Now().UTC()
+	page := &statusPage{Title: title, UpdatedAt: now}
+
+	search := storage.SiteSearch{OrganizationID: organizationID, Public: true, Sort: storage.SortByName}
+	sites, _, err := h.store.SearchSites(search)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make(map[string]*statusComponent)
+	names := make(map[uint]string, len(sites))
+	counts := make(map[string]int)
+	for _, site := range sites {
+		history, err := h.stats.History(site.ID, statusPageDays)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := statusSite{
+			Name:    site.Name,
+			Status:  services.SiteStatus(site, now),
+			History: history,
+		}
+		entry.StatusText = statusText[entry.Status]
+		if site.State != nil {
+			entry.Uptime = site.State.Uptime90d
+		}
+		counts[entry.Status]++
+		names[site.ID] = site.Name
+
+		name := ungroupedComponent
+		if site.Group != nil {
+			name = site.Group.Name
+		}
+		component, ok := components[name]
+		if !ok {
+			component = &statusComponent{Name: name}
+			components[name] = component
+		}
+		component.Sites = append(component.Sites, entry)
+	}
+
+	for _, component := range components {
+		page.Components = append(page.Components, *component)
+	}
+	sort.Slice(page.Components, func(i, j int) bool {
+		a, b := page.Components[i].Name, page.Components[j].Name
+		if (a == ungroupedComponent) != (b == ungroupedComponent) {
+			return b == ungroupedComponent
+		}
+		return a < b
+	})
+
+	page.Overall = overallStatus(counts)
+	page.OverallText = overallText[page.Overall]
+
+	open := true
+	incidents, err := h.store.ListIncidents(storage.IncidentFilter{OrganizationID: organizationID, Open: &open})
+	if err != nil {
+		return nil, err
+	}
+	for _, incident := range incidents {
+		if name, ok := names[incident.SiteID]; ok {
+			page.Incidents = append(page.Incidents, statusIncident{SiteName: name, StartedAt: incident.StartedAt})
+		}
+	}
+
+	return page, nil
+}
+
+// Situação geral pelos status dos sites monitorados (pausados e sem dados não contam)
+func overallStatus(counts map[string]int) string {
+	down := counts[string(checker.StatusDown)]
+	monitored := down + counts[string(checker.StatusUp)] + counts[string(checker.StatusDegraded)]
+
+	switch {
+	case down > 0 && down == monitored:
+		return overallMajor
+	case down > 0:
+		return overallPartial
+	case counts[string(checker.StatusDegraded)] > 0:
+		return overallDegraded
+	}
+	return overallOperational
+}
+
+// Duração aproximada para leitura ("3h 12m", "2d 4h")
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "less than a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+}
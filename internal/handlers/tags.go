@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// GET /api/tags
+func (h *Handler) GetTags(c *gin.Context) {
+	tags, err := h.store.ListTags(tenant(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// DELETE /api/tags/:name remove a tag de todos os sites da organização
+func (h *Handler) DeleteTag(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	name := strings.ToLower(c.Param("name"))
+	if err := h.store.DeleteTag(tenant(c), name); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag não encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover tag"})
+		return
+	}
+	h.audit(c, models.AuditTagDelete, auditTarget{kind: models.AuditTargetTag, name: name}, gin.H{"name": name}, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag removida com sucesso"})
+}
+
+// IDs dos sites que a requisição pode ver, com a tag quando informada (nil para todos os
+// da organização), para filtrar logs e incidentes junto com o filtro por organização
+func (h *Handler) scopedSiteIDs(c *gin.Context, tag string) ([]uint, error) {
+	visible, err := h.visibleSites(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return visible, nil
+	}
+	tagged, err := h.store.SiteIDsByTag(tenant(c), tag)
+	if err != nil {
+		return nil, err
+	}
+	return intersectIDs(visible, tagged), nil
+}
+
+// GET /api/groups
+func (h *Handler) GetGroups(c *gin.Context) {
+	groups, err := h.store.ListGroups(tenant(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar grupos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// POST /api/groups
+func (h *Handler) CreateGroup(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	var request models.GroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group := models.Group{
+		OrganizationID: tenant(c),
+		Name:           strings.TrimSpace(request.Name),
+		Description:    request.Description,
+	}
+	if err := h.store.CreateGroup(&group); err != nil {
+		groupError(c, err)
+		return
+	}
+	h.audit(c, models.AuditGroupCreate, groupTarget(group), nil, groupAudit(group))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Grupo criado com sucesso",
+		"group":   group,
+	})
+}
+
+// PUT /api/groups/:id
+func (h *Handler) UpdateGroup(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var request models.GroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.organizationGroup(c, uint(id))
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+
+	before := groupAudit(*group)
+	group.Name = strings.TrimSpace(request.Name)
+	group.Description = request.Description
+	if err := h.store.UpdateGroup(group); err != nil {
+		groupError(c, err)
+		return
+	}
+	h.audit(c, models.AuditGroupUpdate, groupTarget(*group), before, groupAudit(*group))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Grupo atualizado com sucesso",
+		"group":   group,
+	})
+}
+
+// DELETE /api/groups/:id: os sites do grupo ficam sem grupo
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	group, err := h.organizationGroup(c, uint(id))
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	if err := h.store.DeleteGroup(uint(id)); err != nil {
+		groupError(c, err)
+		return
+	}
+	h.audit(c, models.AuditGroupDelete, groupTarget(*group), groupAudit(*group), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Grupo removido com sucesso"})
+}
+
+// Grupo da organização da requisição; grupos de outras organizações dão ErrNotFound
+func (h *Handler) organizationGroup(c *gin.Context, id uint) (*models.Group, error) {
+	group, err := h.store.GetGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if group.OrganizationID != tenant(c) {
+		return nil, storage.ErrNotFound
+	}
+	return group, nil
+}
+
+func groupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grupo não encontrado"})
+	case errors.Is(err, storage.ErrDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe um grupo com este nome"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar grupo"})
+	}
+}
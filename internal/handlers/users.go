@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luacarol/website-monitoring/internal/auth"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// GET /api/users: usuários da organização
+func (h *Handler) GetUsers(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	users, err := h.store.ListUsers(tenant(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuários"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users, "total": len(users)})
+}
+
+// POST /api/users
+func (h *Handler) CreateUser(c *gin.Context) {
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	var request models.UserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash, err := auth.HashPassword(request.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário"})
+		return
+	}
+
+	user := models.User{
+		OrganizationID: tenant(c),
+		Username:       strings.TrimSpace(request.Username),
+		PasswordHash:   hash,
+	}
+	switch err := h.store.CreateUser(&user); {
+	case errors.Is(err, storage.ErrDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe um usuário com este nome"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário"})
+		return
+	}
+	h.audit(c, models.AuditUserCreate, userTarget(user), nil, userAudit(user))
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Usuário criado com sucesso", "user": user})
+}
+
+// PUT /api/users/:id/password: a própria senha, confirmando a atual, ou, para admins
+// globais, a de qualquer usuário. Todas as sessões do usuário são encerradas; quem trocou a
+// própria senha recebe uma sessão nova
+func (h *Handler) UpdatePassword(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+	p, _ := currentPrincipal(c)
+	self := p.User.ID == uint(id)
+	if !self && !h.authorizeGlobal(c) {
+		return
+	}
+	user, ok := h.userParam(c)
+	if !ok {
+		return
+	}
+
+	var request models.PasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if self {
+		if request.CurrentPassword == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a senha atual em current_password"})
+			return
+		}
+		if !auth.CheckPassword(user.PasswordHash, request.CurrentPassword) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Senha atual incorreta"})
+			return
+		}
+	}
+
+	hash, err := auth.HashPassword(request.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao alterar senha"})
+		return
+	}
+
+	switch err := h.store.UpdatePassword(uint(id), hash); {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao alterar senha"})
+	default:
+		h.audit(c, models.AuditUserPassword, userTarget(*user), nil, nil)
+		if self {
+			if _, ok := h.startSession(c, user.ID); !ok {
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Senha alterada com sucesso"})
+	}
+}
+
+// DELETE /api/users/:id
+func (h *Handler) DeleteUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+	if !h.authorizeGlobal(c) {
+		return
+	}
+
+	if p, _ := currentPrincipal(c); p.User.ID == uint(id) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Não é possível remover o próprio usuário"})
+		return
+	}
+	user, ok := h.userParam(c)
+	if !ok {
+		return
+	}
+
+	switch err := h.store.DeleteUser(uint(id)); {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover usuário"})
+	default:
+		h.audit(c, models.AuditUserDelete, userTarget(*user), userAudit(*user), nil)
+		c.JSON(http.StatusOK, gin.H{"message": "Usuário removido com sucesso"})
+	}
+}
+
+// GET /api/keys: chaves do usuário logado
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	p, _ := currentPrincipal(c)
+
+	keys, err := h.store.ListAPIKeys(p.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar chaves"})
+		return
+	}
+
+	response := make([]models.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, models.APIKeyResponse{APIKey: key, Scopes: key.ScopeList()})
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": response, "total": len(response)})
+}
+
+// POST /api/keys: a chave só é exibida nesta resposta
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	p, _ := currentPrincipal(c)
+
+	var request models.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, prefix, err := auth.NewKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar chave"})
+		return
+	}
+
+	key := models.APIKey{
+		UserID:  p.User.ID,
+		Name:    request.Name,
+		Prefix:  prefix,
+		KeyHash: auth.HashToken(secret),
+		Scopes:  strings.Join(request.Scopes, ","),
+	}
+	if err := h.store.CreateAPIKey(&key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar chave"})
+		return
+	}
+	h.audit(c, models.AuditKeyCreate, auditTarget{kind: models.AuditTargetAPIKey, id: key.ID, name: key.Name}, nil, keyAudit(key))
+
+	c.JSON(http.StatusCreated, models.CreatedAPIKey{
+		APIKeyResponse: models.APIKeyResponse{APIKey: key, Scopes: key.ScopeList()},
+		Key:            secret,
+	})
+}
+
+// DELETE /api/keys/:id
+func (h *Handler) DeleteAPIKey(c *gin.Context) {
+	p, _ := currentPrincipal(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Nome e escopos da chave, para a auditoria
+	keys, err := h.store.ListAPIKeys(p.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar chaves"})
+		return
+	}
+	target := auditTarget{kind: models.AuditTargetAPIKey, id: uint(id)}
+	var before interface{}
+	for _, key := range keys {
+		if key.ID == uint(id) {
+			target.name = key.Name
+			before = keyAudit(key)
+		}
+	}
+
+	switch err := h.store.DeleteAPIKey(p.User.ID, uint(id)); {
+	case errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Chave não encontrada"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revogar chave"})
+	default:
+		h.audit(c, models.AuditKeyDelete, target, before, nil)
+		c.JSON(http.StatusOK, gin.H{"message": "Chave revogada com sucesso"})
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Maior janela aceita em window
+const maxWindow = 365 * 24 * time.Hour
+
+// Duração de uma janela como "24h", "7d" ou "90d", até 365 dias
+func windowDuration(window string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, errors.New("janela inválida: " + window)
+		}
+		if n > int(maxWindow/(24*time.Hour)) {
+			return 0, errors.New("janela maior que 365 dias: " + window)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return 0, errors.New("janela inválida: " + window)
+	}
+	if d > maxWindow {
+		return 0, errors.New("janela maior que 365 dias: " + window)
+	}
+	return d, nil
+}
+
+// Período pedido na query: window=24h|7d|30d|90d (ou qualquer "Nd"/duração Go até 365d),
+// ou um período livre com from/to em RFC3339 ou AAAA-MM-DD (to exclusivo, padrão agora)
+func parseWindow(c *gin.Context, fallback string) (window string, from, to time.Time, err error) {
+	to = time.Now().UTC()
+
+	if c.Query("from") != "" || c.Query("to") != "" {
+		if c.Query("window") != "" {
+			return "", from, to, errors.New("use window ou from/to, não ambos")
+		}
+		if from, err = parseTime(c.Query("from")); err != nil {
+			return "", from, to, err
+		}
+		if c.Query("to") != "" {
+			if to, err = parseTime(c.Query("to")); err != nil {
+				return "", from, to, err
+			}
+		}
+		if !from.Before(to) {
+			return "", from, to, errors.New("from deve ser anterior a to")
+		}
+		return "custom", from, to, nil
+	}
+
+	window = c.DefaultQuery("window", fallback)
+	d, err := windowDuration(window)
+	if err != nil {
+		return "", from, to, err
+	}
+	return window, to.Add(-d), to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("informe from")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("data inválida: " + value)
+}
+
+// Buckets de 1h permitidos até este tamanho de janela (retenção dos agregados por hora)
+const maxHourlyWindow = 90 * 24 * time.Hour
+
+// Tamanho dos buckets da série: bucket=1h ou 1d, ou automático (1h até 7 dias, depois 1d)
+func parseBucket(c *gin.Context, from, to time.Time) (time.Duration, error) {
+	switch c.Query("bucket") {
+	case "":
+		if to.Sub(from) <= 7*24*time.Hour {
+			return time.Hour, nil
+		}
+		return 24 * time.Hour, nil
+	case "1h":
+		if to.Sub(from) > maxHourlyWindow {
+			return 0, errors.New("bucket=1h permite janelas de até 90 dias")
+		}
+		return time.Hour, nil
+	case "1d":
+		return 24 * time.Hour, nil
+	default:
+		return 0, errors.New("bucket inválido: use 1h ou 1d")
+	}
+}
@@ -0,0 +1,129 @@
+package logfile
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Filtros para a consulta do arquivo de log
+type Filter struct {
+	Site   string    // Trecho da URL do site
+	Since  time.Time // Inclusivo
+	Until  time.Time // Exclusivo
+	Status string    // "online", "offline", "up", "degraded", "down" ou vazio
+}
+
+func (f Filter) Match(record Record) bool {
+	if f.Site != "" && !strings.Contains(record.Site, f.Site) {
+		return false
+	}
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !record.Time.Before(f.Until) {
+		return false
+	}
+
+	switch f.Status {
+	case "", "all":
+		return true
+	case "online":
+		return record.Online
+	case "offline":
+		return !record.Online
+	default:
+		return record.Status == f.Status
+	}
+}
+
+// Percorrer os registros do mais antigo para o mais recente: o log.txt legado da mesma
+// pasta, os backups comprimidos e o arquivo atual. Linhas que não puderem ser interpretadas
+// são ignoradas.
+func Read(path string, filter Filter, fn func(Record) error) error {
+	if legacy := filepath.Join(filepath.Dir(path), legacyFile); legacy != filepath.Clean(path) {
+		err := readFile(legacy, false, filter, fn)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	backups, err := listBackups(path)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups {
+		if err := readFile(backup, true, filter, fn); err != nil {
+			return err
+		}
+	}
+
+	err = readFile(path, false, filter, fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Backups ordenados do mais antigo (maior número) para o mais recente
+func listBackups(path string) ([]string, error) {
+	var backups []string
+	for n := 1; ; n++ {
+		backup := backupPath(path, n)
+		if _, err := os.Stat(backup); err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	for i, j := 0, len(backups)-1; i < j; i, j = i+1, j-1 {
+		backups[i], backups[j] = backups[j], backups[i]
+	}
+	return backups, nil
+}
+
+func readFile(path string, compressed bool, filter Filter, fn func(Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		record, err := parseLine(line)
+		if err != nil || !filter.Match(record) {
+			continue
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,75 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// O log.txt das versões antigas é lido antes do JSONL, com o horário local
+func TestReadIncludesLegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	legacy := "02/01/2024 15:04:05 - https://www.example.com - online: true\n" +
+		"linha inválida\n" +
+		"02/01/2024 15:04:35 - https://www.example.org - online: false\n"
+	if err := os.WriteFile(filepath.Join(dir, "log.txt"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	current := `{"time":"2024-01-03T10:00:00Z","site":"https://www.example.com","online":true,"status":"up","status_code":200,"response_time":120}` + "\n"
+	path := filepath.Join(dir, "log.jsonl")
+	if err := os.WriteFile(path, []byte(current), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []Record
+	err := Read(path, Filter{}, func(record Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("%d registros, quer 3: %+v", len(records), records)
+	}
+	want := time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)
+	if first := records[0]; !first.Time.Equal(want) || first.Site != "https://www.example.com" || !first.Online || first.Status != "up" {
+		t.Errorf("primeiro registro = %+v", first)
+	}
+	if second := records[1]; second.Online || second.Status != "down" {
+		t.Errorf("segundo registro = %+v", second)
+	}
+	if records[2].StatusCode != 200 {
+		t.Errorf("último registro = %+v, quer o do JSONL", records[2])
+	}
+
+	var offline int
+	err = Read(path, Filter{Status: "offline"}, func(Record) error {
+		offline++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offline != 1 {
+		t.Errorf("%d registros offline, quer 1", offline)
+	}
+}
+
+// Apontar o próprio log.txt não lê o arquivo duas vezes
+func TestReadLegacyFileDirectly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("02/01/2024 15:04:05 - https://www.example.com - online: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	if err := Read(path, Filter{}, func(Record) error { count++; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("%d registros, quer 1", count)
+	}
+}
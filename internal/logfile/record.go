@@ -0,0 +1,55 @@
+package logfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Arquivo de log das versões antigas da CLI, lido junto com o JSONL da mesma pasta
+const legacyFile = "log.txt"
+
+// Formato legado do log.txt: "02/01/2006 15:04:05 - url - online: true"
+const legacyTimeLayout = "02/01/2006 15:04:05"
+
+// Uma linha do arquivo de log (JSONL)
+type Record struct {
+	Time         time.Time `json:"time"`
+	Site         string    `json:"site"`
+	Online       bool      `json:"online"`
+	Status       string    `json:"status,omitempty"` // "up", "degraded" ou "down"
+	StatusCode   int       `json:"status_code"`
+	ResponseTime int64     `json:"response_time"` // em millisegundos
+	Error        string    `json:"error,omitempty"`
+}
+
+// Interpretar uma linha JSON ou do formato legado
+func parseLine(line string) (Record, error) {
+	var record Record
+
+	if strings.HasPrefix(line, "{") {
+		err := json.Unmarshal([]byte(line), &record)
+		return record, err
+	}
+
+	parts := strings.Split(line, " - ")
+	if len(parts) != 3 || !strings.HasPrefix(parts[2], "online: ") {
+		return record, fmt.Errorf("linha de log inválida: %q", line)
+	}
+
+	checkedAt, err := time.ParseInLocation(legacyTimeLayout, parts[0], time.Local)
+	if err != nil {
+		return record, err
+	}
+
+	record.Time = checkedAt
+	record.Site = parts[1]
+	record.Online = strings.TrimPrefix(parts[2], "online: ") == "true"
+	if record.Online {
+		record.Status = "up"
+	} else {
+		record.Status = "down"
+	}
+	return record, nil
+}
@@ -0,0 +1,62 @@
+package logfile
+
+import (
+	"sort"
+	"time"
+)
+
+// Resumo de disponibilidade de um site a partir dos registros do arquivo
+type SiteSummary struct {
+	Site            string    `json:"site"`
+	Checks          int       `json:"checks"`
+	Online          int       `json:"online"`
+	Degraded        int       `json:"degraded"`
+	Offline         int       `json:"offline"`
+	Uptime          float64   `json:"uptime"`
+	AvgResponseTime int64     `json:"avg_response_time"` // em millisegundos
+	FirstCheck      time.Time `json:"first_check"`
+	LastCheck       time.Time `json:"last_check"`
+}
+
+// Agrupar os registros por site, ordenados pela URL
+func Summarize(records []Record) []SiteSummary {
+	bySite := make(map[string]*SiteSummary)
+	totalTime := make(map[string]int64)
+
+	for _, record := range records {
+		summary, ok := bySite[record.Site]
+		if !ok {
+			summary = &SiteSummary{Site: record.Site, FirstCheck: record.Time}
+			bySite[record.Site] = summary
+		}
+
+		summary.Checks++
+		if record.Online {
+			summary.Online++
+		} else {
+			summary.Offline++
+		}
+		if record.Status == "degraded" {
+			summary.Degraded++
+		}
+		if record.Time.Before(summary.FirstCheck) {
+			summary.FirstCheck = record.Time
+		}
+		if record.Time.After(summary.LastCheck) {
+			summary.LastCheck = record.Time
+		}
+		totalTime[record.Site] += record.ResponseTime
+	}
+
+	summaries := make([]SiteSummary, 0, len(bySite))
+	for site, summary := range bySite {
+		summary.Uptime = float64(summary.Online) / float64(summary.Checks) * 100
+		summary.AvgResponseTime = totalTime[site] / int64(summary.Checks)
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Site < summaries[j].Site
+	})
+	return summaries
+}
@@ -0,0 +1,113 @@
+package logfile
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Writer acrescenta registros ao arquivo e faz a rotação por tamanho.
+// Arquivos rotacionados são comprimidos como <path>.1.gz, <path>.2.gz, ... (maior = mais antigo)
+type Writer struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+}
+
+func NewWriter(path string, maxSize int64, maxBackups int) *Writer {
+	return &Writer{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+}
+
+func (w *Writer) Append(record Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if w.maxSize > 0 {
+		info, err := os.Stat(w.path)
+		if err == nil && info.Size()+int64(len(line)) > w.maxSize {
+			if err := w.rotate(); err != nil {
+				return fmt.Errorf("erro ao rotacionar log: %w", err)
+			}
+		}
+	}
+
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Deslocar os backups e comprimir o arquivo atual como <path>.1.gz
+func (w *Writer) rotate() error {
+	if w.maxBackups <= 0 {
+		return os.Truncate(w.path, 0)
+	}
+
+	oldest := backupPath(w.path, w.maxBackups)
+	if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupPath(w.path, i), backupPath(w.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := compressFile(w.path, backupPath(w.path, 1)); err != nil {
+		return err
+	}
+	return os.Remove(w.path)
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d.gz", path, n)
+}
+
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// Resumo de uma série de tempos de resposta (millisegundos)
+type Latency struct {
+	Min int64 `json:"min"`
+	Avg int64 `json:"avg"`
+	Max int64 `json:"max"`
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P95 int64 `json:"p95"`
+	P99 int64 `json:"p99"`
+}
+
+// Calcular mínimo, média, máximo e percentis. A slice é ordenada no lugar
+func Summarize(values []int64) Latency {
+	if len(values) == 0 {
+		return Latency{}
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	var sum int64
+	for _, value := range values {
+		sum += value
+	}
+
+	return Latency{
+		Min: values[0],
+		Avg: sum / int64(len(values)),
+		Max: values[len(values)-1],
+		P50: Percentile(values, 50),
+		P90: Percentile(values, 90),
+		P95: Percentile(values, 95),
+		P99: Percentile(values, 99),
+	}
+}
+
+// Percentil pelo método nearest-rank sobre valores já ordenados
+func Percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// Resumo de um grupo de medições e quantas medições ele representa
+type Group struct {
+	Count   int64
+	Latency Latency
+}
+
+// Combinar resumos de grupos distintos. Mínimo, máximo e média são exatos;
+// os percentis são aproximados pela média ponderada pela quantidade de medições
+func Combine(groups []Group) (Latency, int64) {
+	var combined Latency
+	var count int64
+
+	for _, group := range groups {
+		if group.Count == 0 {
+			continue
+		}
+		if count == 0 || group.Latency.Min < combined.Min {
+			combined.Min = group.Latency.Min
+		}
+		if group.Latency.Max > combined.Max {
+			combined.Max = group.Latency.Max
+		}
+
+		combined.Avg += group.Latency.Avg * group.Count
+		combined.P50 += group.Latency.P50 * group.Count
+		combined.P90 += group.Latency.P90 * group.Count
+		combined.P95 += group.Latency.P95 * group.Count
+		combined.P99 += group.Latency.P99 * group.Count
+		count += group.Count
+	}
+
+	if count > 0 {
+		combined.Avg /= count
+		combined.P50 /= count
+		combined.P90 /= count
+		combined.P95 /= count
+		combined.P99 /= count
+	}
+	return combined, count
+}
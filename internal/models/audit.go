@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+)
+
+// Registro de uma alteração feita pela API: quem, o quê, em qual registro e o que mudou.
+// Os registros nunca são alterados nem removidos, nem quando o alvo é apagado
+type AuditEntry struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID uint   `json:"organization_id" gorm:"not null;index:idx_audit_entries_organization_created,priority:1"`
+	UserID         uint   `json:"user_id" gorm:"not null;index"`
+	Username       string `json:"username" gorm:"not null"` // Guardado para sobreviver à remoção do usuário
+	APIKeyID       *uint  `json:"api_key_id"`               // nil para alterações feitas com login
+	Action         string `json:"action" gorm:"not null;index"`
+	TargetType     string `json:"target_type" gorm:"not null"`
+	TargetID       uint   `json:"target_id"`
+	TargetName     string `json:"target_name"`
+	Changes        string `json:"-" gorm:"not null"` // JSON: campo -> {before, after}
+	IP             string `json:"ip"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_audit_entries_organization_created,priority:2"`
+}
+
+// Ações registradas
+const (
+	AuditSiteCreate   = "site.create"
+	AuditSiteUpdate   = "site.update"
+	AuditSiteToggle   = "site.toggle"
+	AuditSiteDelete   = "site.delete"
+	AuditSiteRestore  = "site.restore"
+	AuditSitePurge    = "site.purge"
+	AuditSiteImport   = "site.import"
+	AuditSiteCheck    = "site.check"
+	AuditTagDelete    = "tag.delete"
+	AuditGroupCreate  = "group.create"
+	AuditGroupUpdate  = "group.update"
+	AuditGroupDelete  = "group.delete"
+	AuditUserCreate   = "user.create"
+	AuditUserPassword = "user.password"
+	AuditUserDelete   = "user.delete"
+	AuditUserRoles    = "user.roles"
+	AuditUserLogin    = "user.login"
+	AuditUserLogout   = "user.logout"
+	AuditKeyCreate    = "key.create"
+	AuditKeyDelete    = "key.delete"
+	AuditIncidentAck  = "incident.ack"
+)
+
+// Tipos de alvo
+const (
+	AuditTargetSite     = "site"
+	AuditTargetTag      = "tag"
+	AuditTargetGroup    = "group"
+	AuditTargetUser     = "user"
+	AuditTargetAPIKey   = "api_key"
+	AuditTargetIncident = "incident"
+)
+
+// Valor de um campo antes e depois da alteração (null quando o registro não existia ou
+// deixou de existir)
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Registro listado em GET /api/audit
+type AuditEntryResponse struct {
+	AuditEntry
+	Changes map[string]AuditChange `json:"changes"`
+}
+
+type AuditQuery struct {
+	Actor      string `form:"actor"` // Nome do usuário
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   uint   `form:"target_id"`
+	StartDate  string `form:"start_date"`
+	EndDate    string `form:"end_date"`
+	Page       int    `form:"page" binding:"min=0"`
+	Limit      int    `form:"limit" binding:"min=0,max=1000"`
+}
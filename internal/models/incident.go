@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Período em que um site ficou fora do ar, aberto na primeira falha e resolvido na recuperação
+type Incident struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	SiteID     uint       `json:"site_id" gorm:"not null;index"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	Cause      string     `json:"cause"`
+	StatusCode int        `json:"status_code"`
+
+	// Reconhecimento por um operador
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	AcknowledgedBy string     `json:"acknowledged_by"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relacionamento
+	Site Site `json:"site,omitempty" gorm:"foreignKey:SiteID"`
+}
+
+type IncidentsQuery struct {
+	SiteID uint   `form:"site_id"`
+	Status string `form:"status"` // "open", "resolved", "all"
+	Limit  int    `form:"limit"`
+}
+
+func (i Incident) IsOpen() bool {
+	return i.ResolvedAt == nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/luacarol/website-monitoring/internal/metrics"
 	"gorm.io/gorm"
 )
 
@@ -12,6 +13,8 @@ type MonitorLog struct {
 	StatusCode   int            `json:"status_code"`
 	ResponseTime int64          `json:"response_time"` // em millisegundos
 	IsOnline     bool           `json:"is_online"`
+	Status       string         `json:"status"`     // "up", "degraded" ou "down"
+	CheckType    string         `json:"check_type"` // "http", "tcp" ou "dns"
 	ErrorMessage string         `json:"error_message"`
 	CheckedAt    time.Time      `json:"checked_at"`
 	CreatedAt    time.Time      `json:"created_at"`
@@ -22,12 +25,19 @@ type MonitorLog struct {
 }
 
 type LogsQuery struct {
-	SiteID    uint   `form:"site_id"`
-	StartDate string `form:"start_date"`
-	EndDate   string `form:"end_date"`
-	Status    string `form:"status"` // "online", "offline", "all"
-	Page      int    `form:"page"`
-	Limit     int    `form:"limit"`
+	SiteID          uint   `form:"site_id"`
+	StartDate       string `form:"start_date"`
+	EndDate         string `form:"end_date"`
+	Status          string `form:"status"`      // "online", "offline", "all"
+	StatusCode      string `form:"status_code"` // Ex.: "503", "500-599", "4xx,5xx" ou "0" (sem resposta)
+	MinResponseTime *int64 `form:"min_response_time" binding:"omitempty,min=0"`
+	MaxResponseTime *int64 `form:"max_response_time" binding:"omitempty,min=0"`
+	Error           string `form:"error"` // Trecho da mensagem de erro
+	CheckType       string `form:"check_type" binding:"omitempty,oneof=http tcp dns"`
+	Tag             string `form:"tag"`
+	Cursor          string `form:"cursor"` // next_cursor da página anterior (substitui page)
+	Page            int    `form:"page" binding:"min=0"`
+	Limit           int    `form:"limit" binding:"min=0,max=1000"`
 }
 
 type StatsResponse struct {
@@ -36,4 +46,39 @@ type StatsResponse struct {
 	OfflineSites  int       `json:"offline_sites"`
 	OverallUptime float64   `json:"overall_uptime"`
 	LastUpdate    time.Time `json:"last_update"`
+
+	// Com ?tag=: os números acima são dos sites com a tag, e Groups os separa por grupo
+	Tag    string       `json:"tag,omitempty"`
+	Groups []GroupStats `json:"groups,omitempty"`
+
+	// Estatísticas da janela pedida em /api/stats (ausentes no WebSocket)
+	*WindowStats
+}
+
+// Checks, uptime e tempos de resposta em uma janela, com a série para gráficos.
+// Nos trechos já agregados, os percentis são aproximados (média ponderada por hora/dia)
+type WindowStats struct {
+	Window       string           `json:"window"`
+	From         time.Time        `json:"from"`
+	To           time.Time        `json:"to"`
+	Checks       int64            `json:"checks"`
+	Successes    int64            `json:"successes"`
+	Uptime       *float64         `json:"uptime"`        // Ponderado pelo tempo, null sem dados
+	ResponseTime *metrics.Latency `json:"response_time"` // Millisegundos, null sem checks
+	BucketSize   string           `json:"bucket_size"`   // "1h" ou "1d"
+	Series       []StatsPoint     `json:"series"`
+}
+
+// Um bucket da série temporal
+type StatsPoint struct {
+	Time         time.Time        `json:"time"`
+	Checks       int64            `json:"checks"`
+	Uptime       *float64         `json:"uptime"`
+	ResponseTime *metrics.Latency `json:"response_time"`
+}
+
+// GET /api/sites/:id/stats
+type SiteStatsResponse struct {
+	SiteID uint `json:"site_id"`
+	WindowStats
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Organização criada pela migration para os dados anteriores ao multi-tenant. Também é a
+// organização vista por quem lê sem login e a da página de status no endereço padrão
+const DefaultOrganizationID = 1
+
+// Cliente (tenant) do monitor. Sites, grupos e usuários pertencem a uma organização; logs,
+// incidentes, chaves de API e a página de status seguem a organização do site ou do usuário
+type Organization struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	Slug string `json:"slug" gorm:"not null;uniqueIndex"` // Endereço da página de status
+
+	// Cotas, definidas por quem hospeda o monitor (0 para sem limite)
+	MaxSites                int `json:"max_sites" gorm:"not null;default:0"`
+	MinCheckIntervalSeconds int `json:"min_check_interval_seconds" gorm:"not null;default:0"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+func ValidSlug(slug string) bool {
+	return slugPattern.MatchString(slug)
+}
+
+// Se a organização já tem os sites permitidos pela cota
+func (o Organization) SiteQuotaReached(sites int64) bool {
+	return o.MaxSites > 0 && sites >= int64(o.MaxSites)
+}
+
+// GET /api/organization
+type OrganizationResponse struct {
+	Organization
+	Sites int64 `json:"sites"` // Sites não removidos, contados na cota
+}
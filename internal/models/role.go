@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Papéis, do menor para o maior. Cada um inclui as permissões dos anteriores
+const (
+	RoleViewer   = "viewer"   // Ver sites, logs, incidentes e estatísticas
+	RoleOperator = "operator" // Verificar sites na hora e reconhecer incidentes
+	RoleAdmin    = "admin"    // Editar sites, grupos, tags e usuários
+)
+
+var roleRanks = map[string]int{RoleViewer: 1, RoleOperator: 2, RoleAdmin: 3}
+
+// Se o papel inclui as permissões de required
+func RoleAllows(role, required string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
+func ValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// O maior dos dois papéis
+func MaxRole(a, b string) string {
+	if roleRanks[b] > roleRanks[a] {
+		return b
+	}
+	return a
+}
+
+// Papel de um usuário em um grupo de sites, ou em todos os sites quando GroupID é nil
+// (inclusive os sem grupo). Apenas admins globais gerenciam usuários, grupos e tags
+type RoleBinding struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;index"`
+	GroupID   *uint     `json:"group_id"`
+	Role      string    `json:"role" gorm:"not null"`
+	CreatedAt time.Time `json:"-"`
+}
+
+type RoleRequest struct {
+	GroupID *uint  `json:"group_id"` // null ou 0 para todos os sites
+	Role    string `json:"role" binding:"required,oneof=viewer operator admin"`
+}
+
+// PUT /api/users/:id/roles
+type RolesRequest struct {
+	Roles []RoleRequest `json:"roles" binding:"dive"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/metrics"
+)
+
+// Períodos de agregação dos logs
+const (
+	RollupHourly = "hour"
+	RollupDaily  = "day"
+)
+
+// Agregado dos checks de um site em uma hora ou um dia (UTC), gerado pelo job de retenção
+type LogRollup struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	SiteID      uint      `json:"site_id" gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	Period      string    `json:"period" gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	BucketStart time.Time `json:"bucket_start" gorm:"not null;uniqueIndex:idx_rollup_bucket"`
+	Checks      int64     `json:"checks"`
+	Successes   int64     `json:"successes"`
+
+	// Tempo em millisegundos que o site passou online e offline no bucket.
+	// O restante do bucket não tem estado conhecido
+	UpTimeMs   int64 `json:"up_time_ms"`
+	DownTimeMs int64 `json:"down_time_ms"`
+
+	// Tempos de resposta em millisegundos
+	MinResponseTime int64 `json:"min_response_time"`
+	AvgResponseTime int64 `json:"avg_response_time"`
+	MaxResponseTime int64 `json:"max_response_time"`
+	P50ResponseTime int64 `json:"p50_response_time"`
+	P90ResponseTime int64 `json:"p90_response_time"`
+	P95ResponseTime int64 `json:"p95_response_time"`
+	P99ResponseTime int64 `json:"p99_response_time"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Resumo dos tempos de resposta do bucket, com o número de checks como peso
+func (r LogRollup) LatencyGroup() metrics.Group {
+	return metrics.Group{
+		Count: r.Checks,
+		Latency: metrics.Latency{
+			Min: r.MinResponseTime,
+			Avg: r.AvgResponseTime,
+			Max: r.MaxResponseTime,
+			P50: r.P50ResponseTime,
+			P90: r.P90ResponseTime,
+			P95: r.P95ResponseTime,
+			P99: r.P99ResponseTime,
+		},
+	}
+}
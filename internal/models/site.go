@@ -3,21 +3,46 @@ package models
 import (
 	"time"
 
+	"github.com/luacarol/website-monitoring/internal/checker"
 	"gorm.io/gorm"
 )
 
 type Site struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"not null"`
-	URL       string         `json:"url" gorm:"not null;unique"`
-	Active    bool           `json:"active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint           `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_sites_organization_url"`
+	Name           string         `json:"name" gorm:"not null"`
+	URL            string         `json:"url" gorm:"not null;uniqueIndex:idx_sites_organization_url"`
+	Active         bool           `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Configuração da verificação
+	CheckType           string `json:"check_type" gorm:"default:http"`
+	ExpectedStatus      string `json:"expected_status"` // Ex.: "200-399"
+	Keyword             string `json:"keyword"`
+	TimeoutSeconds      int    `json:"timeout_seconds"`
+	DegradedThresholdMs int64  `json:"degraded_threshold_ms"`
+	// Intervalo entre os checks, múltiplo de CheckInterval (0 para o padrão)
+	CheckIntervalSeconds int `json:"check_interval_seconds" gorm:"not null;default:0"`
+
+	// Incrementada a cada alteração; base do ETag e do controle de concorrência
+	Version int `json:"version" gorm:"not null;default:1"`
+
+	// Tags e grupo
+	Tags    []Tag  `json:"tags" gorm:"many2many:site_tags"`
+	GroupID *uint  `json:"group_id" gorm:"index"`
+	Group   *Group `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+
+	// Exibido na página de status pública
+	Public bool `json:"public" gorm:"not null;default:false"`
 
 	// Relacionamento com logs
 	Logs []MonitorLog `json:"logs,omitempty" gorm:"foreignKey:SiteID"`
 
+	// Estado atual, carregado apenas na listagem
+	State *CurrentState `json:"-" gorm:"foreignKey:SiteID"`
+
 	// Campos computados (não salvos no banco)
 	LastStatus *int       `json:"last_status,omitempty" gorm:"-"`
 	LastCheck  *time.Time `json:"last_check,omitempty" gorm:"-"`
@@ -25,13 +50,159 @@ type Site struct {
 }
 
 type SiteRequest struct {
-	Name string `json:"name" binding:"required"`
-	URL  string `json:"url" binding:"required,url"`
+	Name                 string   `json:"name" binding:"required"`
+	URL                  string   `json:"url" binding:"required,url"`
+	Active               *bool    `json:"active"` // Padrão: true
+	CheckType            string   `json:"check_type" binding:"omitempty,oneof=http tcp dns"`
+	ExpectedStatus       string   `json:"expected_status"`
+	Keyword              string   `json:"keyword"`
+	TimeoutSeconds       int      `json:"timeout_seconds" binding:"omitempty,min=1,max=60"`
+	DegradedThresholdMs  int64    `json:"degraded_threshold_ms" binding:"omitempty,min=0"`
+	CheckIntervalSeconds int      `json:"check_interval_seconds" binding:"omitempty,min=0,max=3600"`
+	Tags                 []string `json:"tags"`
+	GroupID              *uint    `json:"group_id"`
+	Public               bool     `json:"public"`
+}
+
+// PATCH /api/sites/:id: apenas os campos informados são alterados
+type SitePatch struct {
+	Name                 *string   `json:"name" binding:"omitempty,min=1"`
+	URL                  *string   `json:"url" binding:"omitempty,url"`
+	Active               *bool     `json:"active"`
+	CheckType            *string   `json:"check_type" binding:"omitempty,oneof=http tcp dns"`
+	ExpectedStatus       *string   `json:"expected_status"`
+	Keyword              *string   `json:"keyword"`
+	TimeoutSeconds       *int      `json:"timeout_seconds" binding:"omitempty,min=0,max=60"`
+	DegradedThresholdMs  *int64    `json:"degraded_threshold_ms" binding:"omitempty,min=0"`
+	CheckIntervalSeconds *int      `json:"check_interval_seconds" binding:"omitempty,min=0,max=3600"`
+	Tags                 *[]string `json:"tags"`
+	GroupID              *uint     `json:"group_id"` // 0 remove o site do grupo
+	Public               *bool     `json:"public"`
+}
+
+type SitesQuery struct {
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"` // Sem page/limit, todos os sites
+	Sort  string `form:"sort"`  // "name", "status", "uptime", "latency" (padrão: id)
+	Order string `form:"order"` // "asc" ou "desc"
+	Q     string `form:"q"`     // Busca por nome ou URL
+	Tag   string `form:"tag"`
+	Group uint   `form:"group"` // ID do grupo
+}
+
+// Site na lixeira
+type DeletedSite struct {
+	Site
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 type SiteResponse struct {
 	Site
-	LastStatus int       `json:"last_status"`
-	LastCheck  time.Time `json:"last_check"`
-	Uptime     float64   `json:"uptime"`
+	Status           string    `json:"status"` // up, degraded, down, unknown ou paused
+	LastStatus       int       `json:"last_status"`
+	LastCheck        time.Time `json:"last_check"`
+	LastResponseTime int64     `json:"last_response_time"`
+	Uptime           float64   `json:"uptime"` // Últimas 24h
+
+	// Uptime ponderado pelo tempo (null sem dados na janela)
+	Uptime7d  *float64 `json:"uptime_7d"`
+	Uptime30d *float64 `json:"uptime_30d"`
+	Uptime90d *float64 `json:"uptime_90d"`
+}
+
+// Converter a configuração do site para o verificador compartilhado
+func (s Site) CheckConfig() checker.Config {
+	checkType := checker.Type(s.CheckType)
+	if checkType == "" {
+		checkType = checker.TypeFor(s.URL)
+	}
+
+	return checker.Config{
+		Type:           checkType,
+		Target:         s.URL,
+		Timeout:        time.Duration(s.TimeoutSeconds) * time.Second,
+		ExpectedStatus: s.ExpectedStatus,
+		Keyword:        s.Keyword,
+		DegradedAfter:  time.Duration(s.DegradedThresholdMs) * time.Millisecond,
+	}
+}
+
+// Estados do site além dos resultados de check (up, degraded, down)
+const (
+	StateUnknown = "unknown" // Sem check recente
+	StatePaused  = "paused"  // Monitoramento desativado
+)
+
+// Estado atual do site. Since marca a última transição entre online e offline
+type SiteState struct {
+	Status          string      `json:"status"`
+	Since           *time.Time  `json:"since"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	LatestCheck     *MonitorLog `json:"latest_check"`
+}
+
+// Estado de um dia na barra de uptime
+const (
+	DayUp      = "up"      // 100% online
+	DayPartial = "partial" // Quedas curtas (uptime de pelo menos 95%)
+	DayDown    = "down"
+	DayNoData  = "no_data"
+)
+
+type DailyStatus struct {
+	Date   string   `json:"date"` // AAAA-MM-DD (UTC)
+	Status string   `json:"status"`
+	Uptime *float64 `json:"uptime"`
+	Checks int64    `json:"checks"`
+}
+
+// GET /api/sites/:id
+type SiteDetail struct {
+	Site            Site                `json:"site"`
+	State           SiteState           `json:"state"`
+	Uptime          map[string]*float64 `json:"uptime"` // Por janela: 24h, 7d, 30d, 90d
+	RecentIncidents []Incident          `json:"recent_incidents"`
+	History         []DailyStatus       `json:"history"` // Últimos 90 dias, do mais antigo ao atual
+}
+
+// Resultado de cada linha da importação
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped" // URL já cadastrada
+	ImportFailed  = "failed"
+)
+
+type ImportRow struct {
+	Line   int    `json:"line"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+	SiteID uint   `json:"site_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// POST /api/sites/import
+type ImportResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Total   int         `json:"total"`
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Skipped int         `json:"skipped"`
+	Failed  int         `json:"failed"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+func (r *ImportResult) Add(row ImportRow) {
+	switch row.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportFailed:
+		r.Failed++
+	}
+	r.Total++
+	r.Rows = append(r.Rows, row)
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// Uptime ponderado pelo tempo por janela, recalculado periodicamente (null sem dados)
+type SiteUptimes struct {
+	Uptime24h *float64 `json:"uptime_24h"`
+	Uptime7d  *float64 `json:"uptime_7d"`
+	Uptime30d *float64 `json:"uptime_30d"`
+	Uptime90d *float64 `json:"uptime_90d"`
+}
+
+// Estado atual de cada site (tabela site_states), atualizado pelo monitor a cada check
+// para que a listagem não precise consultar os logs
+type CurrentState struct {
+	SiteID       uint      `json:"site_id" gorm:"primaryKey;autoIncrement:false"`
+	Status       string    `json:"status"` // "up", "degraded" ou "down"
+	IsOnline     bool      `json:"is_online"`
+	StatusCode   int       `json:"status_code"`
+	ResponseTime int64     `json:"response_time"` // em millisegundos
+	ErrorMessage string    `json:"error_message"`
+	CheckedAt    time.Time `json:"checked_at"`
+	StateSince   time.Time `json:"state_since"` // Última transição entre online e offline
+
+	SiteUptimes
+	UptimeUpdatedAt *time.Time `json:"uptime_updated_at"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (CurrentState) TableName() string { return "site_states" }
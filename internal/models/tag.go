@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Etiqueta livre de um site (ex.: "producao", "api", "cliente:acme"). Serializada só pelo nome
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Name)
+}
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]{0,49}$`)
+
+// Normalizar nomes de tags (minúsculas, sem repetições, em ordem alfabética)
+func NormalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !tagPattern.MatchString(name) {
+			return nil, fmt.Errorf("tag inválida: %q (letras minúsculas, números e _.:-, até 50 caracteres)", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func TagsFromNames(names []string) []Tag {
+	tags := make([]Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, Tag{Name: name})
+	}
+	return tags
+}
+
+func TagNames(tags []Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// GET /api/tags
+type TagCount struct {
+	Name  string `json:"name"`
+	Sites int64  `json:"sites"`
+}
+
+// Grupo nomeado de sites (ex.: um produto). Cada site pertence a no máximo um grupo
+type Group struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;default:1;uniqueIndex:idx_site_groups_organization_name"`
+	Name           string    `json:"name" gorm:"not null;uniqueIndex:idx_site_groups_organization_name"`
+	Description    string    `json:"description"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// "groups" é palavra reservada em alguns bancos
+func (Group) TableName() string { return "site_groups" }
+
+type GroupRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+// Números de um grupo em GET /api/stats?tag=...
+type GroupStats struct {
+	GroupID      *uint    `json:"group_id"` // null para os sites sem grupo
+	Name         string   `json:"name"`
+	TotalSites   int      `json:"total_sites"` // Sites ativos
+	OnlineSites  int      `json:"online_sites"`
+	OfflineSites int      `json:"offline_sites"`
+	Uptime       *float64 `json:"uptime"` // Ponderado pelo tempo na janela, null sem dados
+}
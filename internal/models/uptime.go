@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Uptime de um site em uma janela, ponderado pelo tempo que cada estado durou
+type UptimeReport struct {
+	Window string    `json:"window"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	// Percentual do tempo conhecido em que o site esteve online (null sem dados na janela)
+	Uptime      *float64 `json:"uptime"`
+	UpSeconds   float64  `json:"up_seconds"`
+	DownSeconds float64  `json:"down_seconds"`
+	// Tempo sem estado conhecido: monitor parado, site pausado ou checks atrasados
+	UnknownSeconds float64 `json:"unknown_seconds"`
+	// Parte da janela anterior ao cadastro do site
+	NoDataSeconds float64 `json:"no_data_seconds"`
+	// Percentual do tempo desde o cadastro com estado conhecido
+	Coverage float64 `json:"coverage"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Conta de acesso ao dashboard e à API. A senha é guardada apenas como hash bcrypt
+type User struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;default:1;index"`
+	Username       string    `json:"username" gorm:"not null;uniqueIndex"` // Único entre as organizações (login)
+	PasswordHash   string    `json:"-" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Sessão de login do dashboard. O token fica no cookie; o banco guarda apenas o hash
+type Session struct {
+	TokenHash string    `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// Escopos das chaves de API
+const (
+	ScopeRead  = "read"  // Apenas rotas de leitura
+	ScopeWrite = "write" // Leitura e alterações
+)
+
+// Chave de API de um usuário para automação. Como nas sessões, só o hash é guardado
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"` // Início da chave, para identificá-la
+	KeyHash    string     `json:"-" gorm:"not null;uniqueIndex"`
+	Scopes     string     `json:"-" gorm:"not null"` // Separados por vírgula
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+func (APIKey) TableName() string { return "api_keys" }
+
+func (k APIKey) ScopeList() []string {
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, granted := range k.ScopeList() {
+		// write inclui read
+		if granted == scope || granted == ScopeWrite && scope == ScopeRead {
+			return true
+		}
+	}
+	return false
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UserRequest struct {
+	Username string `json:"username" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,min=8,max=72"` // Limite do bcrypt
+}
+
+type PasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"max=72"` // Obrigatória para a própria senha
+	Password        string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type APIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=read write"`
+}
+
+// Chave listada em GET /api/keys
+type APIKeyResponse struct {
+	APIKey
+	Scopes []string `json:"scopes"`
+}
+
+// POST /api/keys: a chave completa só aparece nesta resposta
+type CreatedAPIKey struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/checker"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Uptime mínimo de um dia com quedas curtas na barra de uptime
+const partialUptime = 95.0
+
+// Status exibido para o site a partir do estado atual carregado em site.State:
+// paused quando desativado e unknown sem check recente
+func SiteStatus(site models.Site, now time.Time) string {
+	switch {
+	case !site.Active:
+		return models.StatePaused
+	case site.State == nil || now.Sub(site.State.CheckedAt) > stateAge(site.CheckIntervalSeconds):
+		return models.StateUnknown
+	case site.State.Status == "" && site.State.IsOnline:
+		return string(checker.StatusUp)
+	case site.State.Status == "":
+		return string(checker.StatusDown)
+	default:
+		return site.State.Status
+	}
+}
+
+// Estado atual do site pelo último check e desde quando ele está online ou offline
+func (s *StatsService) State(site models.Site) (*models.SiteState, error) {
+	now := time.Now().UTC()
+
+	latest, err := s.store.LatestLog(site.ID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	current, err := s.store.GetCurrentState(site.ID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	site.State = current
+	state := &models.SiteState{Status: SiteStatus(site, now), LatestCheck: latest}
+	if state.Status != models.StatePaused && state.Status != models.StateUnknown {
+		since := current.StateSince.UTC()
+		state.Since = &since
+		state.DurationSeconds = now.Sub(since).Seconds()
+	}
+
+	return state, nil
+}
+
+// Estado de cada um dos últimos days dias (UTC), do mais antigo ao atual
+func (s *StatsService) History(siteID uint, days int) ([]models.DailyStatus, error) {
+	now := time.Now().UTC()
+	from := now.Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+
+	points, err := s.Series(storage.Scope{SiteID: siteID}, from, now, 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]models.DailyStatus, 0, len(points))
+	for _, point := range points {
+		day := models.DailyStatus{
+			Date:   point.Time.Format("2006-01-02"),
+			Status: models.DayNoData,
+			Uptime: point.Uptime,
+			Checks: point.Checks,
+		}
+		if point.Uptime != nil {
+			switch uptime := *point.Uptime; {
+			case uptime >= 100:
+				day.Status = models.DayUp
+			case uptime >= partialUptime:
+				day.Status = models.DayPartial
+			default:
+				day.Status = models.DayDown
+			}
+		}
+		history = append(history, day)
+	}
+
+	return history, nil
+}
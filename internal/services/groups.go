@@ -0,0 +1,91 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Nome dos sites sem grupo nas estatísticas por grupo
+const ungroupedName = "Sem grupo"
+
+// Sites ativos, online e offline e uptime em [from, to) dos sites da busca (por tag, grupo
+// ou IDs), no total e separados por grupo. Online e offline seguem o último check, como em Overview
+func (s *StatsService) SitesOverview(search storage.SiteSearch, from, to time.Time) (*models.StatsResponse, error) {
+	sites, _, err := s.store.SearchSites(search)
+	if err != nil {
+		return nil, err
+	}
+	durations, err := s.DurationsBySite(storage.Scope{OrganizationID: search.OrganizationID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupTotals struct {
+		stats     models.GroupStats
+		durations storage.StateDurations
+	}
+	groups := make(map[uint]*groupTotals)
+	var overall groupTotals
+
+	for _, site := range sites {
+		var key uint
+		if site.GroupID != nil {
+			key = *site.GroupID
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &groupTotals{stats: models.GroupStats{GroupID: site.GroupID, Name: ungroupedName}}
+			if site.Group != nil {
+				group.stats.Name = site.Group.Name
+			}
+			groups[key] = group
+		}
+
+		for _, totals := range []*groupTotals{group, &overall} {
+			totals.durations = totals.durations.Add(durations[site.ID])
+			if !site.Active {
+				continue
+			}
+			totals.stats.TotalSites++
+			if site.State == nil {
+				continue
+			}
+			if site.State.IsOnline {
+				totals.stats.OnlineSites++
+			} else {
+				totals.stats.OfflineSites++
+			}
+		}
+	}
+
+	response := &models.StatsResponse{
+		TotalSites:    overall.stats.TotalSites,
+		OnlineSites:   overall.stats.OnlineSites,
+		OfflineSites:  overall.stats.OfflineSites,
+		OverallUptime: overall.durations.Uptime(),
+		LastUpdate:    time.Now().UTC(),
+		Tag:           search.Tag,
+		Groups:        make([]models.GroupStats, 0, len(groups)),
+	}
+	for _, group := range groups {
+		if group.durations.Known() > 0 {
+			uptime := group.durations.Uptime()
+			group.stats.Uptime = &uptime
+		}
+		response.Groups = append(response.Groups, group.stats)
+	}
+
+	// Grupos por nome, com os sites sem grupo no fim
+	sort.Slice(response.Groups, func(i, j int) bool {
+		a, b := response.Groups[i], response.Groups[j]
+		if (a.GroupID == nil) != (b.GroupID == nil) {
+			return b.GroupID == nil
+		}
+		return a.Name < b.Name
+	})
+
+	return response, nil
+}
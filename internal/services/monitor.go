@@ -1,23 +1,44 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"log"
-	"net/http"
 	"time"
 
-	"github.com/luacarol/website-monitoring/internal/database"
+	"github.com/luacarol/website-monitoring/internal/checker"
 	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
 )
 
+// Intervalo padrão entre as verificações de cada site e o passo dos intervalos próprios
+const CheckInterval = 30 * time.Second
+
+// Intervalo entre os checks de um site com check_interval_seconds (0 para o padrão)
+func siteInterval(seconds int) time.Duration {
+	interval := time.Duration(seconds) * time.Second
+	if interval < CheckInterval {
+		return CheckInterval
+	}
+	return interval
+}
+
 type MonitorService struct {
 	isRunning bool
 	stopChan  chan bool
+	checker   *checker.Checker
+	store     storage.Store
+	lastRun   map[uint]time.Time // Último check agendado de cada site, só no loop do monitor
 }
 
-func NewMonitorService() *MonitorService {
+// Os checks só conectam aos destinos permitidos pela política
+func NewMonitorService(store storage.Store, policy *checker.Policy) *MonitorService {
 	return &MonitorService{
 		isRunning: false,
 		stopChan:  make(chan bool),
+		checker:   checker.NewWithPolicy(policy),
+		store:     store,
+		lastRun:   make(map[uint]time.Time),
 	}
 }
 
@@ -33,7 +54,7 @@ func (m *MonitorService) Start() {
 
 	// Goroutine para monitoramento contínuo
 	go func() {
-		ticker := time.NewTicker(30 * time.Second) // Check a cada 30 segundos
+		ticker := time.NewTicker(CheckInterval)
 		defer ticker.Stop()
 
 		for {
@@ -56,89 +77,164 @@ func (m *MonitorService) Stop() {
 	}
 }
 
-// Verificar todos os sites ativos
+// Verificar os sites ativos cujo intervalo já passou desde o último check
 func (m *MonitorService) checkAllSites() {
-	db := database.GetDB()
-
-	var sites []models.Site
-	if err := db.Where("active = ?", true).Find(&sites).Error; err != nil {
+	due, total, err := m.dueSites(time.Now())
+	if err != nil {
 		log.Printf("❌ Erro ao buscar sites: %v", err)
 		return
 	}
 
-	log.Printf("🔍 Verificando %d sites...", len(sites))
+	log.Printf("🔍 Verificando %d de %d sites...", len(due), total)
 
-	for _, site := range sites {
+	for _, site := range due {
 		go m.checkSite(site) // Verificação paralela
 	}
 }
 
-// Verificar um site específico
-func (m *MonitorService) checkSite(site models.Site) {
-	startTime := time.Now()
-
-	// Fazer requisição HTTP
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// Sites ativos cujo intervalo já passou em now, marcados como agendados, e o total de sites
+// ativos. O intervalo mínimo da organização vale também para sites salvos antes dele ou
+// fora da API
+func (m *MonitorService) dueSites(now time.Time) ([]models.Site, int, error) {
+	sites, err := m.store.ListSites(storage.SiteFilter{ActiveOnly: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	organizations, err := m.store.ListOrganizations()
+	if err != nil {
+		return nil, 0, err
+	}
+	minimums := make(map[uint]int, len(organizations))
+	for _, organization := range organizations {
+		minimums[organization.ID] = organization.MinCheckIntervalSeconds
 	}
 
-	response, err := client.Get(site.URL)
+	due := make([]models.Site, 0, len(sites))
+	for _, site := range sites {
+		interval := siteInterval(max(site.CheckIntervalSeconds, minimums[site.OrganizationID]))
+		// Meio tick de folga para o atraso do ticker não pular um check
+		if last, ok := m.lastRun[site.ID]; ok && now.Sub(last) < interval-CheckInterval/2 {
+			continue
+		}
+		m.lastRun[site.ID] = now
+		due = append(due, site)
+	}
+	return due, len(sites), nil
+}
 
-	// Calcular tempo de resposta
-	responseTime := time.Since(startTime).Milliseconds()
+// Verificar um site específico
+func (m *MonitorService) checkSite(site models.Site) *models.MonitorLog {
+	result := m.checker.Check(context.Background(), site.CheckConfig())
 
 	// Criar log entry
 	monitorLog := models.MonitorLog{
 		SiteID:       site.ID,
-		ResponseTime: responseTime,
-		CheckedAt:    time.Now(),
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime.Milliseconds(),
+		IsOnline:     result.IsOnline(),
+		Status:       string(result.Status),
+		CheckType:    string(result.Type),
+		ErrorMessage: result.Error,
+		CheckedAt:    result.CheckedAt.UTC(),
 	}
 
-	if err != nil {
-		// Erro na requisição
-		monitorLog.IsOnline = false
-		monitorLog.StatusCode = 0
-		monitorLog.ErrorMessage = err.Error()
-
-		log.Printf("❌ %s - OFFLINE: %v", site.Name, err)
-	} else {
-		defer response.Body.Close()
-
-		monitorLog.StatusCode = response.StatusCode
-
-		if response.StatusCode >= 200 && response.StatusCode < 400 {
-			monitorLog.IsOnline = true
-			log.Printf("✅ %s - ONLINE (%d) - %dms", site.Name, response.StatusCode, responseTime)
+	switch result.Status {
+	case checker.StatusUp:
+		log.Printf("✅ %s - ONLINE (%d) - %dms", site.Name, monitorLog.StatusCode, monitorLog.ResponseTime)
+	case checker.StatusDegraded:
+		log.Printf("🐢 %s - LENTO (%d) - %dms", site.Name, monitorLog.StatusCode, monitorLog.ResponseTime)
+	default:
+		if monitorLog.StatusCode != 0 {
+			log.Printf("⚠️ %s - PROBLEMA (%d) - %dms", site.Name, monitorLog.StatusCode, monitorLog.ResponseTime)
 		} else {
-			monitorLog.IsOnline = false
-			log.Printf("⚠️ %s - PROBLEMA (%d) - %dms", site.Name, response.StatusCode, responseTime)
+			log.Printf("❌ %s - OFFLINE: %s", site.Name, result.Error)
 		}
 	}
 
 	// Salvar no banco
-	db := database.GetDB()
-	if err := db.Create(&monitorLog).Error; err != nil {
+	if err := m.store.CreateLog(&monitorLog); err != nil {
 		log.Printf("❌ Erro ao salvar log para %s: %v", site.Name, err)
 	}
+
+	m.updateState(site, monitorLog)
+	m.trackIncident(site, monitorLog)
+
+	return &monitorLog
+}
+
+// Atualizar o estado atual do site, mantendo o início do estado enquanto ele não muda
+func (m *MonitorService) updateState(site models.Site, monitorLog models.MonitorLog) {
+	state := models.CurrentState{
+		SiteID:       site.ID,
+		Status:       monitorLog.Status,
+		IsOnline:     monitorLog.IsOnline,
+		StatusCode:   monitorLog.StatusCode,
+		ResponseTime: monitorLog.ResponseTime,
+		ErrorMessage: monitorLog.ErrorMessage,
+		CheckedAt:    monitorLog.CheckedAt,
+		StateSince:   monitorLog.CheckedAt,
+	}
+
+	previous, err := m.store.GetCurrentState(site.ID)
+	switch {
+	case err == nil && previous.IsOnline == monitorLog.IsOnline:
+		state.StateSince = previous.StateSince
+	case err != nil && !errors.Is(err, storage.ErrNotFound):
+		log.Printf("❌ Erro ao buscar estado de %s: %v", site.Name, err)
+		return
+	}
+
+	if err := m.store.SaveCurrentState(&state); err != nil {
+		log.Printf("❌ Erro ao salvar estado de %s: %v", site.Name, err)
+	}
+}
+
+// Abrir incidente na primeira falha e resolvê-lo quando o site voltar
+func (m *MonitorService) trackIncident(site models.Site, monitorLog models.MonitorLog) {
+	incident, err := m.store.OpenIncident(site.ID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("❌ Erro ao buscar incidente de %s: %v", site.Name, err)
+		return
+	}
+
+	switch {
+	case !monitorLog.IsOnline && incident == nil:
+		incident = &models.Incident{
+			SiteID:     site.ID,
+			StartedAt:  monitorLog.CheckedAt,
+			Cause:      monitorLog.ErrorMessage,
+			StatusCode: monitorLog.StatusCode,
+		}
+		switch err := m.store.CreateIncident(incident); {
+		case errors.Is(err, storage.ErrDuplicate):
+			// Outro check simultâneo do site já abriu o incidente
+			return
+		case err != nil:
+			log.Printf("❌ Erro ao abrir incidente de %s: %v", site.Name, err)
+			return
+		}
+		log.Printf("🚨 %s - incidente #%d aberto", site.Name, incident.ID)
+
+	case monitorLog.IsOnline && incident != nil:
+		resolvedAt := monitorLog.CheckedAt
+		incident.ResolvedAt = &resolvedAt
+		if err := m.store.UpdateIncident(incident); err != nil {
+			log.Printf("❌ Erro ao resolver incidente de %s: %v", site.Name, err)
+			return
+		}
+		log.Printf("🟢 %s - incidente #%d resolvido", site.Name, incident.ID)
+	}
 }
 
 // Verificar site individual (para API)
 func (m *MonitorService) CheckSiteNow(siteID uint) *models.MonitorLog {
-	db := database.GetDB()
-
-	var site models.Site
-	if err := db.First(&site, siteID).Error; err != nil {
+	site, err := m.store.GetSite(siteID)
+	if err != nil {
 		log.Printf("❌ Site não encontrado: %d", siteID)
 		return nil
 	}
 
-	m.checkSite(site)
-
-	// Retornar último log
-	var lastLog models.MonitorLog
-	db.Where("site_id = ?", siteID).Order("checked_at desc").First(&lastLog)
-
-	return &lastLog
+	return m.checkSite(*site)
 }
 
 func (m *MonitorService) IsRunning() bool {
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"github.com/luacarol/website-monitoring/internal/storage/memory"
+)
+
+// Um site salvo abaixo do mínimo da organização (ou no padrão) segue o mínimo
+func TestDueSitesUsesOrganizationMinimum(t *testing.T) {
+	store := memory.New()
+	organization := models.Organization{Name: "Acme", Slug: "acme"}
+	if err := store.CreateOrganization(&organization); err != nil {
+		t.Fatal(err)
+	}
+	// Criado direto no store, como fora da API, antes do mínimo existir
+	site := models.Site{OrganizationID: organization.ID, Name: "api", URL: "https://api.example.com", Active: true, CheckIntervalSeconds: 60}
+	if err := store.CreateSite(&site); err != nil {
+		t.Fatal(err)
+	}
+	other := models.Site{Name: "www", URL: "https://www.example.com", Active: true}
+	if err := store.CreateSite(&other); err != nil {
+		t.Fatal(err)
+	}
+
+	organization.MinCheckIntervalSeconds = 300
+	if err := setMinimum(store, organization); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := NewMonitorService(store, nil)
+	start := time.Now()
+	tests := []struct {
+		after time.Duration
+		want  []uint
+	}{
+		{0, []uint{site.ID, other.ID}},
+		{CheckInterval, []uint{other.ID}},
+		{2 * CheckInterval, []uint{other.ID}},
+		{3 * CheckInterval, []uint{other.ID}},
+		{300 * time.Second, []uint{site.ID, other.ID}},
+	}
+	for _, test := range tests {
+		due, total, err := monitor.dueSites(start.Add(test.after))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 {
+			t.Fatalf("total = %d, quer 2", total)
+		}
+		if got := siteIDs(due); !equalIDs(got, test.want) {
+			t.Errorf("após %v: sites %v, quer %v", test.after, got, test.want)
+		}
+	}
+}
+
+// Checks simultâneos do mesmo site (ticker e "verificar agora") abrem um único incidente
+func TestTrackIncidentOpensOneIncident(t *testing.T) {
+	store := memory.New()
+	site := models.Site{Name: "api", URL: "https://api.example.com", Active: true}
+	if err := store.CreateSite(&site); err != nil {
+		t.Fatal(err)
+	}
+	monitor := NewMonitorService(store, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor.trackIncident(site, models.MonitorLog{SiteID: site.ID, CheckedAt: time.Now().UTC(), ErrorMessage: "timeout"})
+		}()
+	}
+	wg.Wait()
+
+	incidents, err := store.ListIncidents(storage.IncidentFilter{SiteID: site.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("%d incidentes abertos, quer 1", len(incidents))
+	}
+
+	monitor.trackIncident(site, models.MonitorLog{SiteID: site.ID, CheckedAt: time.Now().UTC(), IsOnline: true})
+	if _, err := store.OpenIncident(site.ID); err != storage.ErrNotFound {
+		t.Fatalf("incidente ainda aberto após a recuperação: %v", err)
+	}
+}
+
+// Gravar o mínimo sem o ajuste dos sites que UpdateOrganization faz, como em um banco
+// migrado antes do ajuste
+func setMinimum(store *memory.Store, organization models.Organization) error {
+	site, err := store.GetSite(1)
+	if err != nil {
+		return err
+	}
+	interval := site.CheckIntervalSeconds
+	if err := store.UpdateOrganization(&organization); err != nil {
+		return err
+	}
+	site, err = store.GetSite(1)
+	if err != nil {
+		return err
+	}
+	site.CheckIntervalSeconds = interval
+	return store.UpdateSite(site)
+}
+
+func siteIDs(sites []models.Site) []uint {
+	ids := make([]uint, 0, len(sites))
+	for _, site := range sites {
+		ids = append(ids, site.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[uint]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/metrics"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Aguardar checks em andamento antes de consolidar uma hora
+const settleDelay = 2 * time.Minute
+
+type RetentionConfig struct {
+	RawRetention    time.Duration // Logs brutos
+	HourlyRetention time.Duration // Agregados por hora (os diários são mantidos)
+	Interval        time.Duration // Frequência do job
+	UptimeInterval  time.Duration // Frequência do recálculo dos uptimes em cache
+	DeletedSites    time.Duration // Sites na lixeira (0 mantém para sempre)
+}
+
+// Ler LOG_RETENTION_DAYS (padrão 30, mínimo 2), HOURLY_ROLLUP_RETENTION_DAYS (padrão 90)
+// e DELETED_SITE_RETENTION_DAYS (padrão 30, 0 desativa)
+func RetentionConfigFromEnv() RetentionConfig {
+	return RetentionConfig{
+		RawRetention:    envDays("LOG_RETENTION_DAYS", 30, 2),
+		HourlyRetention: envDays("HOURLY_ROLLUP_RETENTION_DAYS", 90, 2),
+		DeletedSites:    envDays("DELETED_SITE_RETENTION_DAYS", 30, 0),
+		Interval:        10 * time.Minute,
+		UptimeInterval:  time.Minute,
+	}
+}
+
+func envDays(name string, fallback, min int) time.Duration {
+	days := fallback
+	if value := os.Getenv(name); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("⚠️ %s inválido (%q), usando %d dias", name, value, fallback)
+		} else {
+			days = parsed
+		}
+	}
+	if days < min {
+		log.Printf("⚠️ %s abaixo do mínimo, usando %d dias", name, min)
+		days = min
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// RetentionService consolida os logs em agregados por hora e por dia, remove os dados antigos
+// e mantém atualizados os uptimes em cache usados na listagem de sites
+type RetentionService struct {
+	store     storage.Store
+	stats     *StatsService
+	config    RetentionConfig
+	isRunning bool
+	stopChan  chan bool
+}
+
+func NewRetentionService(store storage.Store, stats *StatsService, config RetentionConfig) *RetentionService {
+	return &RetentionService{
+		store:    store,
+		stats:    stats,
+		config:   config,
+		stopChan: make(chan bool),
+	}
+}
+
+// Iniciar o job em background
+func (r *RetentionService) Start() {
+	if r.isRunning {
+		return
+	}
+
+	r.isRunning = true
+	log.Printf("🗄️ Retenção: logs brutos por %v, agregados por hora por %v",
+		r.config.RawRetention, r.config.HourlyRetention)
+
+	go func() {
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+		uptimeTicker := time.NewTicker(r.config.UptimeInterval)
+		defer uptimeTicker.Stop()
+
+		r.run()
+		r.refreshUptimes()
+		for {
+			select {
+			case <-ticker.C:
+				r.run()
+			case <-uptimeTicker.C:
+				r.refreshUptimes()
+			case <-r.stopChan:
+				r.isRunning = false
+				return
+			}
+		}
+	}()
+}
+
+func (r *RetentionService) Stop() {
+	if r.isRunning {
+		r.stopChan <- true
+	}
+}
+
+func (r *RetentionService) run() {
+	if err := r.RunOnce(time.Now().UTC()); err != nil {
+		log.Printf("❌ Erro no job de retenção: %v", err)
+	}
+}
+
+func (r *RetentionService) refreshUptimes() {
+	if err := r.stats.RefreshUptimes(time.Now().UTC()); err != nil {
+		log.Printf("❌ Erro ao atualizar uptimes: %v", err)
+	}
+}
+
+// Consolidar horas e dias completos e remover dados fora da retenção
+func (r *RetentionService) RunOnce(now time.Time) error {
+	end := now.Add(-settleDelay).Truncate(time.Hour)
+
+	if err := r.rollupHours(end); err != nil {
+		return err
+	}
+	if err := r.rollupDays(end); err != nil {
+		return err
+	}
+
+	// Logs brutos só são removidos depois de consolidados
+	rawCutoff := minTime(now.Add(-r.config.RawRetention), end)
+	purged, err := r.store.PurgeLogs(rawCutoff)
+	if err != nil {
+		return err
+	}
+
+	dailyEnd := end.Truncate(24 * time.Hour)
+	hourlyCutoff := minTime(now.Add(-r.config.HourlyRetention), dailyEnd)
+	deleted, err := r.store.DeleteRollups(models.RollupHourly, hourlyCutoff)
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 || deleted > 0 {
+		log.Printf("🧹 Retenção: %d logs e %d agregados por hora removidos", purged, deleted)
+	}
+
+	if err := r.purgeDeletedSites(now); err != nil {
+		return err
+	}
+
+	// Sessões de login expiradas
+	if _, err := r.store.DeleteExpiredSessions(now); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apagar definitivamente os sites que estão na lixeira há mais que a retenção
+func (r *RetentionService) purgeDeletedSites(now time.Time) error {
+	if r.config.DeletedSites <= 0 {
+		return nil
+	}
+
+	sites, err := r.store.ListDeletedSites(0, now.Add(-r.config.DeletedSites))
+	if err != nil {
+		return err
+	}
+	for _, site := range sites {
+		if err := r.store.PurgeSite(site.ID); err != nil {
+			return err
+		}
+		log.Printf("🗑️ Site %s (#%d) apagado definitivamente após %v na lixeira", site.Name, site.ID, r.config.DeletedSites)
+	}
+	return nil
+}
+
+// Agregar cada hora completa anterior a end, a partir da última consolidada
+func (r *RetentionService) rollupHours(end time.Time) error {
+	latest, err := r.store.LatestRollupBucket(models.RollupHourly)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if !latest.IsZero() {
+		since = latest.UTC().Add(time.Hour)
+	}
+
+	ages, err := loadStateAges(r.store)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Pular horas sem logs, exceto a hora em que ainda vale um check da anterior
+		next, err := r.store.FirstLogTime(since.Add(-ages.max()))
+		if err != nil {
+			return err
+		}
+		if next.IsZero() {
+			return nil
+		}
+
+		bucket := next.UTC().Truncate(time.Hour)
+		if bucket.Before(since) {
+			bucket = since
+		}
+		if bucket.Add(time.Hour).After(end) {
+			return nil
+		}
+
+		samples, err := r.store.LogSamples(storage.Scope{}, bucket.Add(-ages.max()), bucket.Add(time.Hour))
+		if err != nil {
+			return err
+		}
+		if err := r.store.SaveRollups(hourlyRollups(samples, bucket, ages)); err != nil {
+			return err
+		}
+
+		since = bucket.Add(time.Hour)
+	}
+}
+
+// Agregar cada dia completo anterior a end a partir dos agregados por hora
+func (r *RetentionService) rollupDays(end time.Time) error {
+	latest, err := r.store.LatestRollupBucket(models.RollupDaily)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if !latest.IsZero() {
+		since = latest.UTC().Add(24 * time.Hour)
+	}
+
+	for {
+		// Pular dias sem agregados por hora
+		next, err := r.store.FirstRollupBucket(models.RollupHourly, since)
+		if err != nil {
+			return err
+		}
+		if next.IsZero() {
+			return nil
+		}
+
+		day := next.UTC().Truncate(24 * time.Hour)
+		if day.Add(24 * time.Hour).After(end) {
+			return nil
+		}
+
+		hourly, err := r.store.ListRollups(storage.RollupFilter{
+			Period: models.RollupHourly,
+			Since:  day,
+			Until:  day.Add(24 * time.Hour),
+		})
+		if err != nil {
+			return err
+		}
+		if err := r.store.SaveRollups(dailyRollups(hourly, day)); err != nil {
+			return err
+		}
+
+		since = day.Add(24 * time.Hour)
+	}
+}
+
+// Um agregado por site a partir dos logs da hora (ordenados por site e horário). Logs
+// anteriores à hora só contam para o tempo em cada estado
+func hourlyRollups(samples []models.MonitorLog, bucket time.Time, ages stateAges) []models.LogRollup {
+	var rollups []models.LogRollup
+	durations := stateDurations(samples, bucket, bucket.Add(time.Hour), ages)
+
+	for start := 0; start < len(samples); {
+		end := start
+		for end < len(samples) && samples[end].SiteID == samples[start].SiteID {
+			end++
+		}
+
+		rollup := models.LogRollup{
+			SiteID:      samples[start].SiteID,
+			Period:      models.RollupHourly,
+			BucketStart: bucket,
+		}
+
+		state := durations[rollup.SiteID]
+		rollup.UpTimeMs = state.Up.Milliseconds()
+		rollup.DownTimeMs = state.Down.Milliseconds()
+
+		latencies := make([]int64, 0, end-start)
+		for _, sample := range samples[start:end] {
+			if sample.CheckedAt.Before(bucket) {
+				continue
+			}
+			rollup.Checks++
+			if sample.IsOnline {
+				rollup.Successes++
+			}
+			latencies = append(latencies, sample.ResponseTime)
+		}
+		applyLatency(&rollup, metrics.Summarize(latencies))
+
+		if rollup.Checks > 0 || rollup.UpTimeMs > 0 || rollup.DownTimeMs > 0 {
+			rollups = append(rollups, rollup)
+		}
+		start = end
+	}
+
+	return rollups
+}
+
+// Um agregado por site a partir dos agregados por hora do dia.
+// Os percentis diários são a média dos percentis por hora ponderada pelo número de checks
+func dailyRollups(hourly []models.LogRollup, day time.Time) []models.LogRollup {
+	bySite := make(map[uint]*models.LogRollup)
+	groups := make(map[uint][]metrics.Group)
+	var order []uint
+
+	for _, h := range hourly {
+		daily, ok := bySite[h.SiteID]
+		if !ok {
+			daily = &models.LogRollup{
+				SiteID:      h.SiteID,
+				Period:      models.RollupDaily,
+				BucketStart: day,
+			}
+			bySite[h.SiteID] = daily
+			order = append(order, h.SiteID)
+		}
+
+		daily.Checks += h.Checks
+		daily.Successes += h.Successes
+		daily.UpTimeMs += h.UpTimeMs
+		daily.DownTimeMs += h.DownTimeMs
+		groups[h.SiteID] = append(groups[h.SiteID], h.LatencyGroup())
+	}
+
+	rollups := make([]models.LogRollup, 0, len(order))
+	for _, siteID := range order {
+		daily := bySite[siteID]
+		latency, _ := metrics.Combine(groups[siteID])
+		applyLatency(daily, latency)
+		rollups = append(rollups, *daily)
+	}
+
+	return rollups
+}
+
+func applyLatency(rollup *models.LogRollup, latency metrics.Latency) {
+	rollup.MinResponseTime = latency.Min
+	rollup.AvgResponseTime = latency.Avg
+	rollup.MaxResponseTime = latency.Max
+	rollup.P50ResponseTime = latency.P50
+	rollup.P90ResponseTime = latency.P90
+	rollup.P95ResponseTime = latency.P95
+	rollup.P99ResponseTime = latency.P99
+}
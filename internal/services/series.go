@@ -0,0 +1,130 @@
+package services
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/metrics"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Acumulador de um bucket da série
+type seriesBucket struct {
+	checks    int64
+	durations storage.StateDurations
+	groups    []metrics.Group
+}
+
+// Série em buckets de step (1h ou 1d) em [from, to). Buckets já consolidados vêm dos
+// agregados do mesmo período; o restante, dos logs brutos
+func (s *StatsService) Series(scope storage.Scope, from, to time.Time, step time.Duration) ([]models.StatsPoint, error) {
+	period := models.RollupHourly
+	if step == 24*time.Hour {
+		period = models.RollupDaily
+	}
+
+	from, to = from.UTC().Truncate(step), to.UTC()
+	buckets := make(map[time.Time]*seriesBucket)
+	bucketAt := func(t time.Time) *seriesBucket {
+		key := t.Truncate(step)
+		if buckets[key] == nil {
+			buckets[key] = &seriesBucket{}
+		}
+		return buckets[key]
+	}
+
+	// Buckets cobertos pelos agregados
+	covered, err := s.rollupEnd(period)
+	if err != nil {
+		return nil, err
+	}
+	covered = minTime(covered, to.Truncate(step))
+	if covered.Before(from) {
+		covered = from
+	}
+
+	if from.Before(covered) {
+		rollups, err := s.store.ListRollups(storage.RollupFilter{
+			Scope:  scope,
+			Period: period,
+			Since:  from,
+			Until:  covered,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rollup := range rollups {
+			bucket := bucketAt(rollup.BucketStart.UTC())
+			bucket.checks += rollup.Checks
+			bucket.durations = bucket.durations.Add(storage.StateDurations{
+				Up:   time.Duration(rollup.UpTimeMs) * time.Millisecond,
+				Down: time.Duration(rollup.DownTimeMs) * time.Millisecond,
+			})
+			bucket.groups = append(bucket.groups, rollup.LatencyGroup())
+		}
+	}
+
+	// Restante a partir dos logs brutos
+	if covered.Before(to) {
+		ages, err := s.stateAges()
+		if err != nil {
+			return nil, err
+		}
+		samples, err := s.store.LogSamples(scope, covered.Add(-ages.max()), to)
+		if err != nil {
+			return nil, err
+		}
+
+		latencies := make(map[time.Time][]models.MonitorLog)
+		for _, sample := range samples {
+			if at := sample.CheckedAt.UTC(); !at.Before(covered) {
+				latencies[at.Truncate(step)] = append(latencies[at.Truncate(step)], sample)
+			}
+		}
+
+		for start := covered; start.Before(to); start = start.Add(step) {
+			bucket := bucketAt(start)
+			for _, durations := range stateDurations(samples, start, minTime(start.Add(step), to), ages) {
+				bucket.durations = bucket.durations.Add(durations)
+			}
+			if logs := latencies[start]; len(logs) > 0 {
+				bucket.checks += int64(len(logs))
+				bucket.groups = append(bucket.groups, latencyGroup(logs))
+			}
+		}
+	}
+
+	var points []models.StatsPoint
+	for start := from; start.Before(to); start = start.Add(step) {
+		point := models.StatsPoint{Time: start}
+		if bucket := buckets[start]; bucket != nil {
+			point.Checks = bucket.checks
+			if bucket.durations.Known() > 0 {
+				uptime := bucket.durations.Uptime()
+				point.Uptime = &uptime
+			}
+			if latency, count := metrics.Combine(bucket.groups); count > 0 {
+				point.ResponseTime = &latency
+			}
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// Resumo exato dos tempos de resposta dos logs
+func latencyGroup(samples []models.MonitorLog) metrics.Group {
+	values := make([]int64, 0, len(samples))
+	for _, sample := range samples {
+		values = append(values, sample.ResponseTime)
+	}
+	return metrics.Group{Count: int64(len(values)), Latency: metrics.Summarize(values)}
+}
+
+func formatStep(step time.Duration) string {
+	if step == 24*time.Hour {
+		return "1d"
+	}
+	return "1h"
+}
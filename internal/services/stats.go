@@ -0,0 +1,299 @@
+package services
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/metrics"
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Períodos acima deste tamanho são lidos dos agregados em vez dos logs brutos
+const longRange = 48 * time.Hour
+
+// StatsService calcula estatísticas combinando logs brutos e agregados (rollups)
+type StatsService struct {
+	store storage.Store
+}
+
+func NewStatsService(store storage.Store) *StatsService {
+	return &StatsService{store: store}
+}
+
+// Trecho de um período e a fonte de dados usada para ele ("" para logs brutos)
+type segment struct {
+	period   string
+	from, to time.Time
+}
+
+// Estatísticas gerais: sites online/offline e uptime geral em [from, to) dos sites da
+// organização (0 para todas)
+func (s *StatsService) Overview(organizationID uint, from, to time.Time) (*models.StatsResponse, error) {
+	counts, err := s.store.SiteStatusCounts(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	uptime, err := s.Uptime(storage.Scope{OrganizationID: organizationID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StatsResponse{
+		TotalSites:    int(counts.Total),
+		OnlineSites:   int(counts.Online),
+		OfflineSites:  int(counts.Offline),
+		OverallUptime: uptime,
+		LastUpdate:    now,
+	}, nil
+}
+
+// Percentual do tempo conhecido em [from, to) em que os sites do escopo estiveram online
+// (0 sem dados)
+func (s *StatsService) Uptime(scope storage.Scope, from, to time.Time) (float64, error) {
+	durations, err := s.Durations(scope, from, to)
+	if err != nil {
+		return 0.0, err
+	}
+	return durations.Uptime(), nil
+}
+
+// Checks em [from, to), lendo dos agregados os trechos já consolidados em períodos longos
+func (s *StatsService) CheckCounts(scope storage.Scope, from, to time.Time) (storage.CheckCounts, error) {
+	var total storage.CheckCounts
+
+	segments, err := s.plan(from, to)
+	if err != nil {
+		return total, err
+	}
+
+	for _, seg := range segments {
+		if seg.period == "" {
+			counts, err := s.store.CountChecks(scope, seg.from, seg.to)
+			if err != nil {
+				return total, err
+			}
+			total = total.Add(*counts)
+			continue
+		}
+
+		totals, err := s.store.SumRollups(storage.RollupFilter{
+			Scope:  scope,
+			Period: seg.period,
+			Since:  seg.from,
+			Until:  seg.to,
+		})
+		if err != nil {
+			return total, err
+		}
+		total = total.Add(totals.Checks)
+	}
+
+	return total, nil
+}
+
+// Resumo dos tempos de resposta em [from, to) dos sites do escopo e a quantidade de checks
+// considerados
+func (s *StatsService) Latency(scope storage.Scope, from, to time.Time) (metrics.Latency, int64, error) {
+	segments, err := s.plan(from, to)
+	if err != nil {
+		return metrics.Latency{}, 0, err
+	}
+
+	var groups []metrics.Group
+	for _, seg := range segments {
+		if seg.period == "" {
+			samples, err := s.store.LogSamples(scope, seg.from, seg.to)
+			if err != nil {
+				return metrics.Latency{}, 0, err
+			}
+			groups = append(groups, latencyGroup(samples))
+			continue
+		}
+
+		rollups, err := s.store.ListRollups(storage.RollupFilter{
+			Scope:  scope,
+			Period: seg.period,
+			Since:  seg.from,
+			Until:  seg.to,
+		})
+		if err != nil {
+			return metrics.Latency{}, 0, err
+		}
+		for _, rollup := range rollups {
+			groups = append(groups, rollup.LatencyGroup())
+		}
+	}
+
+	latency, count := metrics.Combine(groups)
+	return latency, count, nil
+}
+
+// Checks, uptime, tempos de resposta e série em buckets de step (1h ou 1d) em [from, to)
+func (s *StatsService) Window(scope storage.Scope, from, to time.Time, step time.Duration) (*models.WindowStats, error) {
+	from, to = from.UTC(), to.UTC()
+
+	counts, err := s.CheckCounts(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	durations, err := s.Durations(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	latency, checks, err := s.Latency(scope, from, to)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.Series(scope, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.WindowStats{
+		From:       from,
+		To:         to,
+		Checks:     counts.Total,
+		Successes:  counts.Online,
+		BucketSize: formatStep(step),
+		Series:     series,
+	}
+	if durations.Known() > 0 {
+		uptime := durations.Uptime()
+		stats.Uptime = &uptime
+	}
+	if checks > 0 {
+		stats.ResponseTime = &latency
+	}
+	return stats, nil
+}
+
+// Dividir [from, to) entre agregados diários, por hora e logs brutos
+func (s *StatsService) plan(from, to time.Time) ([]segment, error) {
+	from, to = from.UTC(), to.UTC()
+	if to.Sub(from) <= longRange {
+		return []segment{{from: from, to: to}}, nil
+	}
+	return s.planRollups(from, to)
+}
+
+// Dividir [from, to) usando os agregados sempre que existirem, mesmo em períodos curtos
+func (s *StatsService) planRollups(from, to time.Time) ([]segment, error) {
+	from, err := s.dataStart(from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	to = to.UTC()
+
+	hourlyEnd, err := s.rollupEnd(models.RollupHourly)
+	if err != nil {
+		return nil, err
+	}
+	dailyEnd, err := s.rollupEnd(models.RollupDaily)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitRange(from, to, hourlyEnd, dailyEnd), nil
+}
+
+// Início de [from, to) a partir do qual ainda há dados para o cálculo. Depois da retenção
+// restam só os agregados diários: uma borda parcial de dia mais antiga que o primeiro
+// agregado por hora (ou de hora, mais antiga que o primeiro log bruto) não tem como ser
+// calculada e é pulada, em vez de contar como tempo sem checks
+func (s *StatsService) dataStart(from, to time.Time) (time.Time, error) {
+	if !from.Before(to) {
+		return from, nil
+	}
+
+	if dayStart := ceilTo(from, 24*time.Hour); from.Before(dayStart) {
+		firstHour, err := s.store.FirstRollupBucket(models.RollupHourly, time.Time{})
+		if err != nil {
+			return from, err
+		}
+		if firstHour = firstHour.UTC(); !firstHour.IsZero() && from.Before(firstHour) {
+			from = minTime(firstHour, dayStart)
+		}
+	}
+
+	if hourStart := ceilTo(from, time.Hour); from.Before(hourStart) {
+		firstLog, err := s.store.FirstLogTime(time.Time{})
+		if err != nil {
+			return from, err
+		}
+		if firstLog = firstLog.UTC(); !firstLog.IsZero() && from.Before(firstLog) {
+			from = minTime(firstLog, hourStart)
+		}
+	}
+
+	return minTime(from, to), nil
+}
+
+// Fim do último bucket agregado do período (zero sem agregados)
+func (s *StatsService) rollupEnd(period string) (time.Time, error) {
+	latest, err := s.store.LatestRollupBucket(period)
+	if err != nil || latest.IsZero() {
+		return time.Time{}, err
+	}
+	return latest.UTC().Add(bucketSize(period)), nil
+}
+
+func splitRange(from, to, hourlyEnd, dailyEnd time.Time) []segment {
+	// Dias completos cobertos pelos agregados diários
+	if !dailyEnd.IsZero() {
+		dayStart := ceilTo(from, 24*time.Hour)
+		dayEnd := minTime(dailyEnd, to.Truncate(24*time.Hour))
+		if dayStart.Before(dayEnd) {
+			segments := splitHourly(from, dayStart, hourlyEnd)
+			segments = append(segments, segment{period: models.RollupDaily, from: dayStart, to: dayEnd})
+			return append(segments, splitHourly(dayEnd, to, hourlyEnd)...)
+		}
+	}
+	return splitHourly(from, to, hourlyEnd)
+}
+
+func splitHourly(from, to, hourlyEnd time.Time) []segment {
+	if !from.Before(to) {
+		return nil
+	}
+
+	hourStart := ceilTo(from, time.Hour)
+	hourEnd := minTime(hourlyEnd, to.Truncate(time.Hour))
+	if hourlyEnd.IsZero() || !hourStart.Before(hourEnd) {
+		return []segment{{from: from, to: to}}
+	}
+
+	var segments []segment
+	if from.Before(hourStart) {
+		segments = append(segments, segment{from: from, to: hourStart})
+	}
+	segments = append(segments, segment{period: models.RollupHourly, from: hourStart, to: hourEnd})
+	if hourEnd.Before(to) {
+		segments = append(segments, segment{from: hourEnd, to: to})
+	}
+	return segments
+}
+
+func bucketSize(period string) time.Duration {
+	if period == models.RollupDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Arredondar para cima até o múltiplo de d (dias contados em UTC)
+func ceilTo(t time.Time, d time.Duration) time.Time {
+	floor := t.Truncate(d)
+	if floor.Equal(t) {
+		return floor
+	}
+	return floor.Add(d)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Tempo máximo que o resultado de um check vale: três intervalos do site. Sem um novo
+// check depois disso (monitor parado, site pausado), o estado do site passa a ser desconhecido
+func stateAge(intervalSeconds int) time.Duration {
+	return 3 * siteInterval(intervalSeconds)
+}
+
+// Validade dos checks dos sites com intervalo próprio; os demais usam o intervalo padrão
+type stateAges map[uint]time.Duration
+
+func (s *StatsService) stateAges() (stateAges, error) {
+	return loadStateAges(s.store)
+}
+
+func loadStateAges(store storage.Store) (stateAges, error) {
+	intervals, err := store.CheckIntervals()
+	if err != nil {
+		return nil, err
+	}
+	ages := make(stateAges, len(intervals))
+	for siteID, seconds := range intervals {
+		ages[siteID] = stateAge(seconds)
+	}
+	return ages, nil
+}
+
+func (a stateAges) of(siteID uint) time.Duration {
+	if age, ok := a[siteID]; ok {
+		return age
+	}
+	return stateAge(0)
+}
+
+// Maior validade, usada para buscar o último check antes de um período
+func (a stateAges) max() time.Duration {
+	longest := stateAge(0)
+	for _, age := range a {
+		if age > longest {
+			longest = age
+		}
+	}
+	return longest
+}
+
+// Uptime do site em [from, to) ponderado pelo tempo em cada estado
+func (s *StatsService) SiteUptime(site models.Site, from, to time.Time) (*models.UptimeReport, error) {
+	from, to = from.UTC(), to.UTC()
+	report := &models.UptimeReport{From: from, To: to}
+
+	// O período anterior ao cadastro não conta como desconhecido, nem a borda já removida
+	// pela retenção
+	start := from
+	if created := site.CreatedAt.UTC(); created.After(start) {
+		start = minTime(created, to)
+	}
+	start, err := s.dataStart(start, to)
+	if err != nil {
+		return nil, err
+	}
+	report.NoDataSeconds = start.Sub(from).Seconds()
+
+	durations, err := s.Durations(storage.Scope{SiteID: site.ID}, start, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report.UpSeconds = durations.Up.Seconds()
+	report.DownSeconds = durations.Down.Seconds()
+	report.UnknownSeconds = (to.Sub(start) - durations.Known()).Seconds()
+	if tracked := to.Sub(start); tracked > 0 {
+		report.Coverage = float64(durations.Known()) / float64(tracked) * 100
+	}
+	if durations.Known() > 0 {
+		uptime := durations.Uptime()
+		report.Uptime = &uptime
+	}
+
+	return report, nil
+}
+
+// Tempo online e offline em [from, to) dos sites do escopo, lendo dos agregados tudo o que
+// já foi consolidado, mesmo em períodos curtos: dos logs brutos vêm só as bordas parciais e
+// a hora ainda não agregada. Roda a cada GET /api/stats e a cada tick do WebSocket
+func (s *StatsService) Durations(scope storage.Scope, from, to time.Time) (storage.StateDurations, error) {
+	var total storage.StateDurations
+
+	segments, err := s.planRollups(from, to)
+	if err != nil {
+		return total, err
+	}
+	ages, err := s.stateAges()
+	if err != nil {
+		return total, err
+	}
+
+	for _, seg := range segments {
+		if seg.period != "" {
+			totals, err := s.store.SumRollups(storage.RollupFilter{
+				Scope:  scope,
+				Period: seg.period,
+				Since:  seg.from,
+				Until:  seg.to,
+			})
+			if err != nil {
+				return total, err
+			}
+			total = total.Add(totals.Durations)
+			continue
+		}
+
+		// Incluir o último check antes do trecho, que ainda define o estado no início dele
+		samples, err := s.store.LogSamples(scope, seg.from.Add(-ages.max()), seg.to)
+		if err != nil {
+			return total, err
+		}
+		for _, durations := range stateDurations(samples, seg.from, seg.to, ages) {
+			total = total.Add(durations)
+		}
+	}
+
+	return total, nil
+}
+
+// Tempo online e offline de cada site do escopo em [from, to), lendo dos agregados tudo o
+// que já foi consolidado. Usado no cálculo em lote dos uptimes em cache
+func (s *StatsService) DurationsBySite(scope storage.Scope, from, to time.Time) (map[uint]storage.StateDurations, error) {
+	segments, err := s.planRollups(from, to)
+	if err != nil {
+		return nil, err
+	}
+	ages, err := s.stateAges()
+	if err != nil {
+		return nil, err
+	}
+
+	bySite := make(map[uint]storage.StateDurations)
+	for _, seg := range segments {
+		if seg.period != "" {
+			totals, err := s.store.SumRollupsBySite(storage.RollupFilter{
+				Scope:  scope,
+				Period: seg.period,
+				Since:  seg.from,
+				Until:  seg.to,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for siteID, total := range totals {
+				bySite[siteID] = bySite[siteID].Add(total.Durations)
+			}
+			continue
+		}
+
+		samples, err := s.store.LogSamples(scope, seg.from.Add(-ages.max()), seg.to)
+		if err != nil {
+			return nil, err
+		}
+		for siteID, durations := range stateDurations(samples, seg.from, seg.to, ages) {
+			bySite[siteID] = bySite[siteID].Add(durations)
+		}
+	}
+
+	return bySite, nil
+}
+
+// Recalcular os uptimes em cache (24h, 7d, 30d, 90d) de todos os sites
+func (s *StatsService) RefreshUptimes(now time.Time) error {
+	windows := []struct {
+		duration time.Duration
+		set      func(*models.SiteUptimes, *float64)
+	}{
+		{24 * time.Hour, func(u *models.SiteUptimes, v *float64) { u.Uptime24h = v }},
+		{7 * 24 * time.Hour, func(u *models.SiteUptimes, v *float64) { u.Uptime7d = v }},
+		{30 * 24 * time.Hour, func(u *models.SiteUptimes, v *float64) { u.Uptime30d = v }},
+		{90 * 24 * time.Hour, func(u *models.SiteUptimes, v *float64) { u.Uptime90d = v }},
+	}
+
+	sites, err := s.store.ListSites(storage.SiteFilter{})
+	if err != nil {
+		return err
+	}
+	uptimes := make(map[uint]models.SiteUptimes, len(sites))
+	for _, site := range sites {
+		uptimes[site.ID] = models.SiteUptimes{}
+	}
+
+	for _, window := range windows {
+		bySite, err := s.DurationsBySite(storage.Scope{}, now.Add(-window.duration), now)
+		if err != nil {
+			return err
+		}
+		for siteID, durations := range bySite {
+			uptime, ok := uptimes[siteID]
+			if !ok || durations.Known() <= 0 {
+				continue
+			}
+			value := durations.Uptime()
+			window.set(&uptime, &value)
+			uptimes[siteID] = uptime
+		}
+	}
+
+	return s.store.SaveUptimes(uptimes, now)
+}
+
+// Tempo em cada estado por site dentro de [from, to). Cada check vale até o próximo
+// check do site ou até a validade dele; logs ordenados por site e horário
+func stateDurations(samples []models.MonitorLog, from, to time.Time, ages stateAges) map[uint]storage.StateDurations {
+	bySite := make(map[uint]storage.StateDurations)
+
+	for i, sample := range samples {
+		start := sample.CheckedAt.UTC()
+		end := start.Add(ages.of(sample.SiteID))
+		if i+1 < len(samples) && samples[i+1].SiteID == sample.SiteID {
+			end = minTime(end, samples[i+1].CheckedAt.UTC())
+		}
+
+		if start.Before(from) {
+			start = from
+		}
+		end = minTime(end, to)
+		if !start.Before(end) {
+			continue
+		}
+
+		durations := bySite[sample.SiteID]
+		if sample.IsOnline {
+			durations.Up += end.Sub(start)
+		} else {
+			durations.Down += end.Sub(start)
+		}
+		bySite[sample.SiteID] = durations
+	}
+
+	return bySite
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage/memory"
+)
+
+// O início de uma janela longa cai em um dia cujas horas e logs já foram removidos pela
+// retenção: esse trecho é reportado sem dados, não como tempo desconhecido
+func TestSiteUptimeSkipsEdgePastRetention(t *testing.T) {
+	store := memory.New()
+	to := time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)
+	from := to.Add(-90 * 24 * time.Hour)
+	firstHour := from.Truncate(time.Hour).Add(time.Hour)
+	lastHour := to.Truncate(time.Hour)
+
+	site := models.Site{Name: "api", URL: "https://api.example.com", Active: true}
+	if err := store.CreateSite(&site); err != nil {
+		t.Fatal(err)
+	}
+	site.CreatedAt = from.Add(-30 * 24 * time.Hour)
+
+	var rollups []models.LogRollup
+	for day := from.Truncate(24 * time.Hour); day.Before(lastHour.Truncate(24 * time.Hour)); day = day.Add(24 * time.Hour) {
+		rollups = append(rollups, models.LogRollup{SiteID: site.ID, Period: models.RollupDaily, BucketStart: day, Checks: 2880, Successes: 2880, UpTimeMs: (24 * time.Hour).Milliseconds()})
+	}
+	// Agregados por hora mantidos só a partir de firstHour
+	for hour := firstHour; hour.Before(lastHour); hour = hour.Add(time.Hour) {
+		rollups = append(rollups, models.LogRollup{SiteID: site.ID, Period: models.RollupHourly, BucketStart: hour, Checks: 120, Successes: 120, UpTimeMs: time.Hour.Milliseconds()})
+	}
+	if err := store.SaveRollups(rollups); err != nil {
+		t.Fatal(err)
+	}
+	for checked := lastHour.Add(-time.Hour); checked.Before(to); checked = checked.Add(CheckInterval) {
+		log := models.MonitorLog{SiteID: site.ID, IsOnline: true, Status: "up", CheckedAt: checked}
+		if err := store.CreateLog(&log); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := NewStatsService(store).SiteUptime(site, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := firstHour.Sub(from).Seconds(); report.NoDataSeconds != want {
+		t.Errorf("sem dados = %vs, quer %vs", report.NoDataSeconds, want)
+	}
+	if report.UnknownSeconds != 0 {
+		t.Errorf("desconhecido = %vs, quer 0", report.UnknownSeconds)
+	}
+	if report.Coverage != 100 {
+		t.Errorf("cobertura = %v%%, quer 100%%", report.Coverage)
+	}
+}
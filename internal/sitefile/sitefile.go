@@ -0,0 +1,409 @@
+package sitefile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+)
+
+// Formatos aceitos na importação e na exportação de sites
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatText Format = "txt" // Uma URL por linha, como o sites.txt da CLI
+)
+
+// Colunas do CSV, na ordem da exportação. Na importação apenas url é obrigatória
+var csvColumns = []string{
+	"name", "url", "active", "check_type", "expected_status",
+	"keyword", "timeout_seconds", "degraded_threshold_ms", "tags", "public",
+	"check_interval_seconds", "group_id",
+}
+
+// Separador das tags na coluna tags do CSV
+const tagSeparator = ";"
+
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimPrefix(name, "."))) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatText, "text":
+		return FormatText, nil
+	}
+	return "", fmt.Errorf("formato desconhecido: %s (use json, csv ou txt)", name)
+}
+
+// Formato pela extensão do arquivo
+func FormatForFile(path string) (Format, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return "", fmt.Errorf("não foi possível identificar o formato de %s", path)
+	}
+	return ParseFormat(ext)
+}
+
+// Formato pelo Content-Type (vazio quando não reconhecido)
+func FormatForContentType(contentType string) Format {
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		return FormatJSON
+	case strings.HasPrefix(contentType, "text/csv"):
+		return FormatCSV
+	case strings.HasPrefix(contentType, "text/plain"):
+		return FormatText
+	}
+	return ""
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Uma linha do arquivo importado. Err é o erro de leitura da linha, se houver
+type Row struct {
+	Line    int // Linha no arquivo (item no JSON, começando em 1)
+	Request models.SiteRequest
+	Fields  map[string]bool // Campos presentes na linha; os demais não vêm do arquivo
+	Err     error
+}
+
+// Requisição de atualização de site: os campos que o arquivo não traz mantêm o valor
+// atual, em vez de voltar ao padrão (um sites.txt só tem a URL)
+func (r Row) Merge(site models.Site) models.SiteRequest {
+	merged := Request(site)
+	request := r.Request
+	for field := range r.Fields {
+		switch field {
+		case "name":
+			merged.Name = request.Name
+		case "url":
+			merged.URL = request.URL
+		case "active":
+			merged.Active = request.Active
+		case "check_type":
+			merged.CheckType = request.CheckType
+		case "expected_status":
+			merged.ExpectedStatus = request.ExpectedStatus
+		case "keyword":
+			merged.Keyword = request.Keyword
+		case "timeout_seconds":
+			merged.TimeoutSeconds = request.TimeoutSeconds
+		case "degraded_threshold_ms":
+			merged.DegradedThresholdMs = request.DegradedThresholdMs
+		case "tags":
+			merged.Tags = request.Tags
+		case "public":
+			merged.Public = request.Public
+		case "check_interval_seconds":
+			merged.CheckIntervalSeconds = request.CheckIntervalSeconds
+		case "group_id":
+			merged.GroupID = request.GroupID
+		}
+	}
+	return merged
+}
+
+// Ler todos os sites do arquivo. Erros em uma linha ficam em Row.Err; o erro retornado
+// indica que o arquivo inteiro é inválido
+func Decode(r io.Reader, format Format) ([]Row, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(r)
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatText:
+		return decodeText(r)
+	}
+	return nil, fmt.Errorf("formato desconhecido: %s", format)
+}
+
+// Lista de sites ou {"sites": [...]}, como na resposta de GET /api/sites
+func decodeJSON(r io.Reader) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapper struct {
+			Sites []json.RawMessage `json:"sites"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, fmt.Errorf("JSON inválido: %w", err)
+		}
+		items = wrapper.Sites
+	} else if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+
+	rows := make([]Row, 0, len(items))
+	for i, item := range items {
+		row := Row{Line: i + 1}
+		if err := json.Unmarshal(item, &row.Request); err != nil {
+			row.Err = fmt.Errorf("item inválido: %w", err)
+		}
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(item, &fields) == nil {
+			row.Fields = make(map[string]bool, len(fields))
+			for name := range fields {
+				row.Fields[strings.ToLower(name)] = true
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// CSV com cabeçalho; as colunas podem vir em qualquer ordem
+func decodeCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CSV inválido: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	fields := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !knownColumn(name) {
+			return nil, fmt.Errorf("coluna desconhecida no CSV: %q", name)
+		}
+		columns[name] = i
+		fields[name] = true
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, errors.New("o CSV precisa de uma coluna url")
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, Row{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line, Fields: fields}
+		row.Request, row.Err = csvRequest(record, columns)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func knownColumn(name string) bool {
+	for _, column := range csvColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func csvRequest(record []string, columns map[string]int) (models.SiteRequest, error) {
+	var request models.SiteRequest
+
+	value := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	request.Name = value("name")
+	request.URL = value("url")
+	request.CheckType = value("check_type")
+	request.ExpectedStatus = value("expected_status")
+	request.Keyword = value("keyword")
+	if tags := value("tags"); tags != "" {
+		request.Tags = strings.Split(tags, tagSeparator)
+	}
+
+	if active := value("active"); active != "" {
+		parsed, err := strconv.ParseBool(active)
+		if err != nil {
+			return request, fmt.Errorf("active inválido: %q", active)
+		}
+		request.Active = &parsed
+	}
+	if public := value("public"); public != "" {
+		parsed, err := strconv.ParseBool(public)
+		if err != nil {
+			return request, fmt.Errorf("public inválido: %q", public)
+		}
+		request.Public = parsed
+	}
+	if timeout := value("timeout_seconds"); timeout != "" {
+		parsed, err := strconv.Atoi(timeout)
+		if err != nil {
+			return request, fmt.Errorf("timeout_seconds inválido: %q", timeout)
+		}
+		request.TimeoutSeconds = parsed
+	}
+	if threshold := value("degraded_threshold_ms"); threshold != "" {
+		parsed, err := strconv.ParseInt(threshold, 10, 64)
+		if err != nil {
+			return request, fmt.Errorf("degraded_threshold_ms inválido: %q", threshold)
+		}
+		request.DegradedThresholdMs = parsed
+	}
+	if interval := value("check_interval_seconds"); interval != "" {
+		parsed, err := strconv.Atoi(interval)
+		if err != nil {
+			return request, fmt.Errorf("check_interval_seconds inválido: %q", interval)
+		}
+		request.CheckIntervalSeconds = parsed
+	}
+	if group := value("group_id"); group != "" {
+		parsed, err := strconv.ParseUint(group, 10, 0)
+		if err != nil {
+			return request, fmt.Errorf("group_id inválido: %q", group)
+		}
+		id := uint(parsed)
+		request.GroupID = &id
+	}
+
+	return request, nil
+}
+
+// Uma URL por linha, ignorando linhas em branco e comentários (mesmas regras da CLI)
+func decodeText(r io.Reader) ([]Row, error) {
+	var rows []Row
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rows = append(rows, Row{
+			Line:    line,
+			Request: models.SiteRequest{Name: NameForURL(text), URL: text},
+			Fields:  map[string]bool{"url": true},
+		})
+	}
+	return rows, scanner.Err()
+}
+
+// Nome padrão de um site sem nome: o host da URL
+func NameForURL(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return target
+	}
+	return strings.TrimPrefix(parsed.Host, "www.")
+}
+
+// Escrever os sites no formato pedido. No sites.txt os sites pausados saem comentados,
+// para que a CLI não os verifique
+func Encode(w io.Writer, format Format, sites []models.Site) error {
+	switch format {
+	case FormatJSON:
+		requests := make([]models.SiteRequest, 0, len(sites))
+		for _, site := range sites {
+			requests = append(requests, Request(site))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(requests)
+
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write(csvColumns)
+		for _, site := range sites {
+			writer.Write([]string{
+				site.Name,
+				site.URL,
+				strconv.FormatBool(site.Active),
+				site.CheckType,
+				site.ExpectedStatus,
+				site.Keyword,
+				strconv.Itoa(site.TimeoutSeconds),
+				strconv.FormatInt(site.DegradedThresholdMs, 10),
+				strings.Join(models.TagNames(site.Tags), tagSeparator),
+				strconv.FormatBool(site.Public),
+				strconv.Itoa(site.CheckIntervalSeconds),
+				groupID(site.GroupID),
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case FormatText:
+		for _, site := range sites {
+			line := site.URL
+			if !site.Active {
+				line = "# " + line
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("formato desconhecido: %s", format)
+}
+
+// Campos editáveis do site, no formato aceito pela importação
+func Request(site models.Site) models.SiteRequest {
+	active := site.Active
+	return models.SiteRequest{
+		Name:                 site.Name,
+		URL:                  site.URL,
+		Active:               &active,
+		CheckType:            site.CheckType,
+		ExpectedStatus:       site.ExpectedStatus,
+		Keyword:              site.Keyword,
+		TimeoutSeconds:       site.TimeoutSeconds,
+		DegradedThresholdMs:  site.DegradedThresholdMs,
+		Tags:                 models.TagNames(site.Tags),
+		GroupID:              site.GroupID,
+		Public:               site.Public,
+		CheckIntervalSeconds: site.CheckIntervalSeconds,
+	}
+}
+
+func groupID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
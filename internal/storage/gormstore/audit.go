@@ -0,0 +1,72 @@
+package gormstore
+
+import (
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+)
+
+func (s *Store) CreateAuditEntry(entry *models.AuditEntry) error {
+	return translate(s.db.Create(entry).Error)
+}
+
+func (s *Store) ListAuditEntries(filter storage.AuditFilter) ([]models.AuditEntry, int64, error) {
+	query := s.filterAudit(s.db.Model(&models.AuditEntry{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translate(err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var entries []models.AuditEntry
+	err := query.Order("created_at desc, id desc").Offset(filter.Offset).Find(&entries).Error
+	return entries, total, translate(err)
+}
+
+func (s *Store) StreamAuditEntries(filter storage.AuditFilter, fn func(models.AuditEntry) error) error {
+	rows, err := s.filterAudit(s.db.Model(&models.AuditEntry{}), filter).Order("created_at, id").Rows()
+	if err != nil {
+		return translate(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		if err := s.db.ScanRows(rows, &entry); err != nil {
+			return translate(err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return translate(rows.Err())
+}
+
+// Aplicar os filtros comuns à listagem e à exportação
+func (s *Store) filterAudit(query *gorm.DB, filter storage.AuditFilter) *gorm.DB {
+	if filter.OrganizationID != 0 {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.Username != "" {
+		query = query.Where("username = ?", filter.Username)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	return query
+}
@@ -0,0 +1,50 @@
+package gormstore
+
+import (
+	"errors"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+)
+
+// Store implementa storage.Store sobre o GORM
+type Store struct {
+	db *gorm.DB
+}
+
+var _ storage.Store = (*Store)(nil)
+
+func New(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Converter erros do GORM para os erros do pacote storage
+func translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return storage.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return storage.ErrDuplicate
+	default:
+		return err
+	}
+}
+
+// Subconsulta com os IDs dos sites da organização, incluindo os removidos
+func organizationSites(db *gorm.DB, organizationID uint) *gorm.DB {
+	return db.Unscoped().Model(&models.Site{}).Select("id").Where("organization_id = ?", organizationID)
+}
+
+// Restringir a coluna com o ID do site (ex.: "site_id") ao escopo
+func inScope(db, query *gorm.DB, column string, scope storage.Scope) *gorm.DB {
+	if scope.SiteID != 0 {
+		query = query.Where(column+" = ?", scope.SiteID)
+	}
+	if scope.OrganizationID != 0 {
+		query = query.Where(column+" IN (?)", organizationSites(db, scope.OrganizationID))
+	}
+	return query
+}
@@ -0,0 +1,60 @@
+package gormstore
+
+import (
+	"github.com/luacarol/website-monitoring/internal/models"
+	"gorm.io/gorm"
+)
+
+func (s *Store) ListGroups(organizationID uint) ([]models.Group, error) {
+	var groups []models.Group
+	err := s.db.Where("organization_id = ?", organizationID).Order("name").Find(&groups).Error
+	return groups, translate(err)
+}
+
+func (s *Store) GetGroup(id uint) (*models.Group, error) {
+	var group models.Group
+	if err := s.db.First(&group, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &group, nil
+}
+
+func (s *Store) CreateGroup(group *models.Group) error {
+	return translate(s.db.Create(group).Error)
+}
+
+func (s *Store) UpdateGroup(group *models.Group) error {
+	result := s.db.Model(group).Select("name", "description").Updates(group)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translate(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (s *Store) DeleteGroup(id uint) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Site{}).Where("group_id = ?", id).Update("group_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&models.RoleBinding{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Group{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}))
+}
+
+func (s *Store) SiteIDsByGroup(ids ...uint) ([]uint, error) {
+	siteIDs := []uint{}
+	err := s.db.Model(&models.Site{}).Where("group_id IN ?", ids).Order("id").Pluck("id", &siteIDs).Error
+	return siteIDs, translate(err)
+}
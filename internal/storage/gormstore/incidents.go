@@ -0,0 +1,70 @@
+package gormstore
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) OpenIncident(siteID uint) (*models.Incident, error) {
+	var incident models.Incident
+	err := s.db.Where("site_id = ? AND resolved_at IS NULL", siteID).Order("started_at desc").First(&incident).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &incident, nil
+}
+
+func (s *Store) CreateIncident(incident *models.Incident) error {
+	return translate(s.db.Create(incident).Error)
+}
+
+func (s *Store) UpdateIncident(incident *models.Incident) error {
+	return translate(s.db.Omit("acknowledged_at", "acknowledged_by").Save(incident).Error)
+}
+
+func (s *Store) GetIncident(id uint) (*models.Incident, error) {
+	var incident models.Incident
+	if err := s.db.Preload("Site").First(&incident, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &incident, nil
+}
+
+func (s *Store) AcknowledgeIncident(id uint, by string, at time.Time) error {
+	result := s.db.Model(&models.Incident{ID: id}).Updates(map[string]interface{}{
+		"acknowledged_at": at,
+		"acknowledged_by": by,
+	})
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListIncidents(filter storage.IncidentFilter) ([]models.Incident, error) {
+	query := s.db.Model(&models.Incident{}).Preload("Site")
+
+	query = inScope(s.db, query, "site_id", storage.Scope{SiteID: filter.SiteID, OrganizationID: filter.OrganizationID})
+	if filter.SiteIDs != nil {
+		query = query.Where("site_id IN ?", filter.SiteIDs)
+	}
+	if filter.Open != nil {
+		if *filter.Open {
+			query = query.Where("resolved_at IS NULL")
+		} else {
+			query = query.Where("resolved_at IS NOT NULL")
+		}
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var incidents []models.Incident
+	err := query.Order("started_at desc").Find(&incidents).Error
+	return incidents, translate(err)
+}
@@ -0,0 +1,129 @@
+package gormstore
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+)
+
+func (s *Store) CreateLog(log *models.MonitorLog) error {
+	return translate(s.db.Create(log).Error)
+}
+
+func (s *Store) LatestLog(siteID uint) (*models.MonitorLog, error) {
+	var lastLog models.MonitorLog
+	if err := s.db.Where("site_id = ?", siteID).Order("checked_at desc").First(&lastLog).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &lastLog, nil
+}
+
+func (s *Store) ListLogs(filter storage.LogFilter) ([]models.MonitorLog, int64, error) {
+	query := s.filterLogs(s.db.Model(&models.MonitorLog{}).Preload("Site"), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translate(err)
+	}
+
+	var logs []models.MonitorLog
+	err := query.Order("checked_at desc, id desc").Limit(filter.Limit).Offset(filter.Offset).Find(&logs).Error
+	return logs, total, translate(err)
+}
+
+func (s *Store) PageLogs(filter storage.LogFilter) ([]models.MonitorLog, error) {
+	var logs []models.MonitorLog
+	err := s.filterLogs(s.db.Model(&models.MonitorLog{}).Preload("Site"), filter).
+		Order("checked_at desc, id desc").Limit(filter.Limit).Find(&logs).Error
+	return logs, translate(err)
+}
+
+func (s *Store) StreamLogs(filter storage.LogFilter, fn func(models.MonitorLog) error) error {
+	rows, err := s.filterLogs(s.db.Model(&models.MonitorLog{}), filter).Order("checked_at, id").Rows()
+	if err != nil {
+		return translate(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log models.MonitorLog
+		if err := s.db.ScanRows(rows, &log); err != nil {
+			return translate(err)
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return translate(rows.Err())
+}
+
+// Aplicar os filtros comuns à listagem e à exportação
+func (s *Store) filterLogs(query *gorm.DB, filter storage.LogFilter) *gorm.DB {
+	query = inScope(s.db, query, "site_id", storage.Scope{SiteID: filter.SiteID, OrganizationID: filter.OrganizationID})
+	if filter.SiteIDs != nil {
+		query = query.Where("site_id IN ?", filter.SiteIDs)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("checked_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("checked_at < ?", filter.Until)
+	}
+	if filter.Online != nil {
+		query = query.Where("is_online = ?", *filter.Online)
+	}
+	if len(filter.StatusCodes) > 0 {
+		conditions := make([]string, 0, len(filter.StatusCodes))
+		var args []interface{}
+		for _, r := range filter.StatusCodes {
+			conditions = append(conditions, "status_code BETWEEN ? AND ?")
+			args = append(args, r.Min, r.Max)
+		}
+		query = query.Where(strings.Join(conditions, " OR "), args...)
+	}
+	if filter.MinLatency != nil {
+		query = query.Where("response_time >= ?", *filter.MinLatency)
+	}
+	if filter.MaxLatency != nil {
+		query = query.Where("response_time <= ?", *filter.MaxLatency)
+	}
+	if filter.ErrorText != "" {
+		query = query.Where("LOWER(error_message) LIKE ?", "%"+strings.ToLower(filter.ErrorText)+"%")
+	}
+	if filter.CheckType != "" {
+		query = query.Where("check_type = ?", filter.CheckType)
+	}
+	if filter.Before != nil {
+		query = query.Where("checked_at < ? OR (checked_at = ? AND id < ?)",
+			filter.Before.CheckedAt, filter.Before.CheckedAt, filter.Before.ID)
+	}
+	return query
+}
+
+func (s *Store) FirstLogTime(since time.Time) (time.Time, error) {
+	var first models.MonitorLog
+	err := s.db.Select("checked_at").Where("checked_at >= ?", since).Order("checked_at").First(&first).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return first.CheckedAt, translate(err)
+}
+
+func (s *Store) LogSamples(scope storage.Scope, from, to time.Time) ([]models.MonitorLog, error) {
+	query := s.db.Select("id, site_id, status_code, response_time, is_online, status, checked_at").
+		Where("checked_at >= ? AND checked_at < ?", from, to)
+	query = inScope(s.db, query, "site_id", scope)
+
+	var logs []models.MonitorLog
+	err := query.Order("site_id, checked_at").Find(&logs).Error
+	return logs, translate(err)
+}
+
+func (s *Store) PurgeLogs(before time.Time) (int64, error) {
+	result := s.db.Unscoped().Where("checked_at < ?", before).Delete(&models.MonitorLog{})
+	return result.RowsAffected, translate(result.Error)
+}
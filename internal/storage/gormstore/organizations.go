@@ -0,0 +1,55 @@
+package gormstore
+
+import (
+	"github.com/luacarol/website-monitoring/internal/models"
+	"gorm.io/gorm"
+)
+
+func (s *Store) ListOrganizations() ([]models.Organization, error) {
+	var organizations []models.Organization
+	err := s.db.Order("id").Find(&organizations).Error
+	return organizations, translate(err)
+}
+
+func (s *Store) GetOrganization(id uint) (*models.Organization, error) {
+	var organization models.Organization
+	if err := s.db.First(&organization, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &organization, nil
+}
+
+func (s *Store) FindOrganizationBySlug(slug string) (*models.Organization, error) {
+	var organization models.Organization
+	if err := s.db.Where("slug = ?", slug).First(&organization).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &organization, nil
+}
+
+func (s *Store) CreateOrganization(organization *models.Organization) error {
+	return translate(s.db.Create(organization).Error)
+}
+
+func (s *Store) UpdateOrganization(organization *models.Organization) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(organization).Select("name", "max_sites", "min_check_interval_seconds").Updates(organization)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Os sites no intervalo padrão (0) também passam a usar o mínimo
+		if organization.MinCheckIntervalSeconds == 0 {
+			return nil
+		}
+		return tx.Unscoped().Model(&models.Site{}).
+			Where("organization_id = ? AND check_interval_seconds < ?", organization.ID, organization.MinCheckIntervalSeconds).
+			Updates(map[string]interface{}{
+				"check_interval_seconds": organization.MinCheckIntervalSeconds,
+				"version":                gorm.Expr("version + 1"),
+			}).Error
+	}))
+}
@@ -0,0 +1,127 @@
+package gormstore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Store) SaveRollups(rollups []models.LogRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "site_id"}, {Name: "period"}, {Name: "bucket_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"checks", "successes", "up_time_ms", "down_time_ms",
+			"min_response_time", "avg_response_time", "max_response_time",
+			"p50_response_time", "p90_response_time", "p95_response_time", "p99_response_time",
+			"updated_at",
+		}),
+	}).CreateInBatches(rollups, 500).Error
+	return translate(err)
+}
+
+func (s *Store) rollupQuery(filter storage.RollupFilter) *gorm.DB {
+	query := s.db.Model(&models.LogRollup{}).Where("period = ?", filter.Period)
+
+	query = inScope(s.db, query, "site_id", filter.Scope)
+	if !filter.Since.IsZero() {
+		query = query.Where("bucket_start >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("bucket_start < ?", filter.Until)
+	}
+	return query
+}
+
+func (s *Store) ListRollups(filter storage.RollupFilter) ([]models.LogRollup, error) {
+	var rollups []models.LogRollup
+	err := s.rollupQuery(filter).Order("site_id, bucket_start").Find(&rollups).Error
+	return rollups, translate(err)
+}
+
+func (s *Store) SumRollups(filter storage.RollupFilter) (*storage.RollupTotals, error) {
+	var sums struct {
+		Checks     int64
+		Successes  int64
+		UpTimeMs   int64
+		DownTimeMs int64
+	}
+	err := s.rollupQuery(filter).
+		Select("COALESCE(SUM(checks), 0) AS checks, COALESCE(SUM(successes), 0) AS successes, " +
+			"COALESCE(SUM(up_time_ms), 0) AS up_time_ms, COALESCE(SUM(down_time_ms), 0) AS down_time_ms").
+		Scan(&sums).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	return &storage.RollupTotals{
+		Checks: storage.CheckCounts{Total: sums.Checks, Online: sums.Successes},
+		Durations: storage.StateDurations{
+			Up:   time.Duration(sums.UpTimeMs) * time.Millisecond,
+			Down: time.Duration(sums.DownTimeMs) * time.Millisecond,
+		},
+	}, nil
+}
+
+func (s *Store) SumRollupsBySite(filter storage.RollupFilter) (map[uint]storage.RollupTotals, error) {
+	var rows []struct {
+		SiteID     uint
+		Checks     int64
+		Successes  int64
+		UpTimeMs   int64
+		DownTimeMs int64
+	}
+	err := s.rollupQuery(filter).
+		Select("site_id, SUM(checks) AS checks, SUM(successes) AS successes, " +
+			"SUM(up_time_ms) AS up_time_ms, SUM(down_time_ms) AS down_time_ms").
+		Group("site_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	totals := make(map[uint]storage.RollupTotals, len(rows))
+	for _, row := range rows {
+		totals[row.SiteID] = storage.RollupTotals{
+			Checks: storage.CheckCounts{Total: row.Checks, Online: row.Successes},
+			Durations: storage.StateDurations{
+				Up:   time.Duration(row.UpTimeMs) * time.Millisecond,
+				Down: time.Duration(row.DownTimeMs) * time.Millisecond,
+			},
+		}
+	}
+	return totals, nil
+}
+
+func (s *Store) LatestRollupBucket(period string) (time.Time, error) {
+	var latest models.LogRollup
+	err := s.db.Select("bucket_start").Where("period = ?", period).Order("bucket_start desc").First(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return latest.BucketStart, translate(err)
+}
+
+func (s *Store) FirstRollupBucket(period string, since time.Time) (time.Time, error) {
+	var first models.LogRollup
+	err := s.db.Select("bucket_start").
+		Where("period = ? AND bucket_start >= ?", period, since).
+		Order("bucket_start").
+		First(&first).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return first.BucketStart, translate(err)
+}
+
+func (s *Store) DeleteRollups(period string, before time.Time) (int64, error) {
+	result := s.db.Where("period = ? AND bucket_start < ?", period, before).Delete(&models.LogRollup{})
+	return result.RowsAffected, translate(result.Error)
+}
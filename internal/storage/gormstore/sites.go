@@ -0,0 +1,268 @@
+package gormstore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Carregar tags (em ordem alfabética) e grupo
+func withTagsAndGroup(query *gorm.DB) *gorm.DB {
+	return query.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.name")
+	}).Preload("Group")
+}
+
+func (s *Store) ListSites(filter storage.SiteFilter) ([]models.Site, error) {
+	query := withTagsAndGroup(s.db.Model(&models.Site{}))
+	if filter.OrganizationID != 0 {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var sites []models.Site
+	err := query.Order("id").Find(&sites).Error
+	return sites, translate(err)
+}
+
+func (s *Store) GetSite(id uint) (*models.Site, error) {
+	var site models.Site
+	if err := withTagsAndGroup(s.db).First(&site, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &site, nil
+}
+
+func (s *Store) CreateSite(site *models.Site) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(site).Error; err != nil {
+			return err
+		}
+		return replaceTags(tx, site)
+	}))
+}
+
+func (s *Store) FindSiteByURL(organizationID uint, url string) (*models.Site, error) {
+	var site models.Site
+	err := withTagsAndGroup(s.db.Unscoped()).Where("organization_id = ? AND url = ?", organizationID, url).First(&site).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &site, nil
+}
+
+func (s *Store) CountSites(organizationID uint) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.Site{}).Where("organization_id = ?", organizationID).Count(&count).Error
+	return count, translate(err)
+}
+
+func (s *Store) CheckIntervals() (map[uint]int, error) {
+	var rows []struct {
+		ID                   uint
+		CheckIntervalSeconds int
+	}
+	effective := "CASE WHEN sites.check_interval_seconds > organizations.min_check_interval_seconds " +
+		"THEN sites.check_interval_seconds ELSE organizations.min_check_interval_seconds END"
+	err := s.db.Unscoped().Model(&models.Site{}).
+		Select("sites.id, " + effective + " AS check_interval_seconds").
+		Joins("JOIN organizations ON organizations.id = sites.organization_id").
+		Where(effective + " > 0").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	intervals := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		intervals[row.ID] = row.CheckIntervalSeconds
+	}
+	return intervals, nil
+}
+
+func (s *Store) UpdateSite(site *models.Site) error {
+	expected := site.Version
+	site.Version++
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(site).
+			Where("version = ?", expected).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(site)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.First(&models.Site{}, site.ID).Error; err != nil {
+				return err
+			}
+			return storage.ErrConflict
+		}
+		return replaceTags(tx, site)
+	})
+	if err != nil {
+		site.Version = expected
+		return translate(err)
+	}
+	return nil
+}
+
+// Soft delete
+func (s *Store) DeleteSite(id uint) error {
+	result := s.db.Delete(&models.Site{}, id)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) SearchSites(search storage.SiteSearch) ([]models.Site, int64, error) {
+	query := s.db.Model(&models.Site{})
+	if search.OrganizationID != 0 {
+		query = query.Where("sites.organization_id = ?", search.OrganizationID)
+	}
+	if search.Query != "" {
+		like := "%" + strings.ToLower(search.Query) + "%"
+		query = query.Where("LOWER(sites.name) LIKE ? OR LOWER(sites.url) LIKE ?", like, like)
+	}
+	if search.Tag != "" {
+		query = query.Where("sites.id IN (?)", tagSites(s.db, search.Tag))
+	}
+	if search.GroupID != 0 {
+		query = query.Where("sites.group_id = ?", search.GroupID)
+	}
+	if search.SiteIDs != nil {
+		query = query.Where("sites.id IN ?", search.SiteIDs)
+	}
+	if search.Public {
+		query = query.Where("sites.public = ?", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translate(err)
+	}
+
+	query = withTagsAndGroup(query).Joins("State").Clauses(clause.OrderBy{Expression: siteOrder(search.Sort, search.Desc)})
+	if search.Limit > 0 {
+		query = query.Offset(search.Offset).Limit(search.Limit)
+	}
+
+	var sites []models.Site
+	err := query.Find(&sites).Error
+	return sites, total, translate(err)
+}
+
+// ORDER BY da listagem, desempatado pelo id. Sites sem estado (e pausados, no status)
+// ficam sempre no fim
+func siteOrder(sort string, desc bool) clause.Expression {
+	stateColumn := func(name string) clause.Column {
+		return clause.Column{Table: "State", Name: name}
+	}
+	id := clause.Column{Table: "sites", Name: "id"}
+
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+
+	var value interface{}
+	switch sort {
+	case storage.SortByName:
+		return clause.Expr{SQL: "?" + direction + ", ?" + direction, Vars: []interface{}{clause.Column{Table: "sites", Name: "name"}, id}}
+	case storage.SortByStatus:
+		value = clause.Expr{
+			SQL:  "CASE WHEN ? IS NULL OR NOT ? THEN NULL WHEN ? = 'down' THEN 0 WHEN ? = 'degraded' THEN 1 ELSE 2 END",
+			Vars: []interface{}{stateColumn("status"), clause.Column{Table: "sites", Name: "active"}, stateColumn("status"), stateColumn("status")},
+		}
+	case storage.SortByUptime:
+		value = stateColumn("uptime24h")
+	case storage.SortByLatency:
+		value = stateColumn("response_time")
+	default:
+		return clause.Expr{SQL: "?" + direction, Vars: []interface{}{id}}
+	}
+
+	return clause.Expr{
+		SQL:  "CASE WHEN ? IS NULL THEN 1 ELSE 0 END, ?" + direction + ", ?" + direction,
+		Vars: []interface{}{value, value, id},
+	}
+}
+
+func (s *Store) ListDeletedSites(organizationID uint, before time.Time) ([]models.Site, error) {
+	query := withTagsAndGroup(s.db.Unscoped()).Where("deleted_at IS NOT NULL")
+	if organizationID != 0 {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if !before.IsZero() {
+		query = query.Where("deleted_at < ?", before)
+	}
+
+	var sites []models.Site
+	err := query.Order("deleted_at desc").Find(&sites).Error
+	return sites, translate(err)
+}
+
+func (s *Store) GetDeletedSite(organizationID, id uint) (*models.Site, error) {
+	var site models.Site
+	err := withTagsAndGroup(s.db.Unscoped()).
+		Where("deleted_at IS NOT NULL AND organization_id = ?", organizationID).
+		First(&site, id).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &site, nil
+}
+
+func (s *Store) RestoreSite(id uint) (*models.Site, error) {
+	var site models.Site
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := withTagsAndGroup(tx.Unscoped()).Where("deleted_at IS NOT NULL").First(&site, id).Error; err != nil {
+			return err
+		}
+		site.DeletedAt = gorm.DeletedAt{}
+		site.Version++
+		return tx.Unscoped().Model(&site).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    site.Version,
+		}).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &site, nil
+}
+
+func (s *Store) PurgeSite(id uint) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().First(&models.Site{}, id).Error; err != nil {
+			return err
+		}
+
+		dependents := []interface{}{
+			&models.MonitorLog{},
+			&models.Incident{},
+			&models.LogRollup{},
+			&models.CurrentState{},
+		}
+		for _, model := range dependents {
+			if err := tx.Unscoped().Where("site_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM site_tags WHERE site_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Site{}, id).Error
+	}))
+}
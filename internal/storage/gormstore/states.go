@@ -0,0 +1,46 @@
+package gormstore
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Store) GetCurrentState(siteID uint) (*models.CurrentState, error) {
+	var state models.CurrentState
+	if err := s.db.First(&state, "site_id = ?", siteID).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &state, nil
+}
+
+func (s *Store) SaveCurrentState(state *models.CurrentState) error {
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "site_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "is_online", "status_code", "response_time", "error_message",
+			"checked_at", "state_since", "updated_at",
+		}),
+	}).Create(state).Error
+	return translate(err)
+}
+
+func (s *Store) SaveUptimes(uptimes map[uint]models.SiteUptimes, at time.Time) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		for siteID, uptime := range uptimes {
+			err := tx.Model(&models.CurrentState{}).Where("site_id = ?", siteID).Updates(map[string]interface{}{
+				"uptime24h":         uptime.Uptime24h,
+				"uptime7d":          uptime.Uptime7d,
+				"uptime30d":         uptime.Uptime30d,
+				"uptime90d":         uptime.Uptime90d,
+				"uptime_updated_at": at,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
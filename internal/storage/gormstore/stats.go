@@ -0,0 +1,62 @@
+package gormstore
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Contagem de online/offline portável entre SQLite e PostgreSQL:
+// CASE sobre a coluna booleana em vez de somar booleanos
+const onlineCountsSQL = "COALESCE(SUM(CASE WHEN ml1.is_online THEN 1 ELSE 0 END), 0) AS online, " +
+	"COALESCE(SUM(CASE WHEN ml1.is_online THEN 0 ELSE 1 END), 0) AS offline"
+
+func (s *Store) SiteStatusCounts(organizationID uint) (*storage.StatusCounts, error) {
+	var counts storage.StatusCounts
+
+	// Total de sites ativos
+	sites := s.db.Model(&models.Site{}).Where("active = ?", true)
+	if organizationID != 0 {
+		sites = sites.Where("organization_id = ?", organizationID)
+	}
+	if err := sites.Count(&counts.Total).Error; err != nil {
+		return nil, translate(err)
+	}
+
+	// Sites online/offline (baseado no último check)
+	// Subquery para pegar o último log de cada site
+	subQuery := s.db.Table("monitor_logs").
+		Select("site_id, MAX(checked_at) AS last_check").
+		Where("deleted_at IS NULL").
+		Group("site_id")
+
+	// Join para pegar o status do último check
+	query := s.db.Table("monitor_logs ml1").
+		Select(onlineCountsSQL).
+		Joins("JOIN (?) ml2 ON ml1.site_id = ml2.site_id AND ml1.checked_at = ml2.last_check", subQuery).
+		Joins("JOIN sites s ON ml1.site_id = s.id AND s.deleted_at IS NULL").
+		Where("s.active = ? AND ml1.deleted_at IS NULL", true)
+	if organizationID != 0 {
+		query = query.Where("s.organization_id = ?", organizationID)
+	}
+	err := query.Scan(&counts).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	return &counts, nil
+}
+
+func (s *Store) CountChecks(scope storage.Scope, from, to time.Time) (*storage.CheckCounts, error) {
+	query := s.db.Table("monitor_logs ml1").
+		Select("COUNT(*) AS total, "+onlineCountsSQL).
+		Where("ml1.deleted_at IS NULL AND ml1.checked_at >= ? AND ml1.checked_at < ?", from, to)
+	query = inScope(s.db, query, "ml1.site_id", scope)
+
+	var counts storage.CheckCounts
+	if err := query.Scan(&counts).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &counts, nil
+}
@@ -0,0 +1,92 @@
+package gormstore
+
+import (
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Gravar as tags do site pelo nome, criando as que não existem. Tags nil mantém as atuais
+func replaceTags(tx *gorm.DB, site *models.Site) error {
+	if site.Tags == nil {
+		return nil
+	}
+
+	tags := []models.Tag{}
+	if len(site.Tags) > 0 {
+		names := models.TagNames(site.Tags)
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+			Create(models.TagsFromNames(names)).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(site).Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	site.Tags = tags
+	return nil
+}
+
+// Subconsulta com os IDs dos sites que têm alguma das tags
+func tagSites(db *gorm.DB, names ...string) *gorm.DB {
+	return db.Table("site_tags").
+		Select("site_tags.site_id").
+		Joins("JOIN tags ON tags.id = site_tags.tag_id").
+		Where("tags.name IN ?", names)
+}
+
+func (s *Store) ListTags(organizationID uint) ([]models.TagCount, error) {
+	var tags []models.TagCount
+	err := s.db.Table("tags").
+		Select("tags.name, COUNT(sites.id) AS sites").
+		Joins("JOIN site_tags ON site_tags.tag_id = tags.id").
+		Joins("JOIN sites ON sites.id = site_tags.site_id AND sites.deleted_at IS NULL").
+		Where("sites.organization_id = ?", organizationID).
+		Group("tags.name").
+		Order("tags.name").
+		Scan(&tags).Error
+	return tags, translate(err)
+}
+
+// A tag sai da tabela quando nenhum site de outra organização a usa
+func (s *Store) DeleteTag(organizationID uint, name string) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		var tag models.Tag
+		if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec("DELETE FROM site_tags WHERE tag_id = ? AND site_id IN (?)", tag.ID, organizationSites(tx, organizationID))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return storage.ErrNotFound
+		}
+
+		var remaining int64
+		if err := tx.Table("site_tags").Where("tag_id = ?", tag.ID).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
+		}
+		return tx.Delete(&tag).Error
+	}))
+}
+
+func (s *Store) SiteIDsByTag(organizationID uint, names ...string) ([]uint, error) {
+	ids := []uint{}
+	err := s.db.Model(&models.Site{}).
+		Where("organization_id = ?", organizationID).
+		Where("id IN (?)", tagSites(s.db, names...)).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, translate(err)
+}
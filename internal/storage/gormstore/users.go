@@ -0,0 +1,159 @@
+package gormstore
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"gorm.io/gorm"
+)
+
+func (s *Store) ListUsers(organizationID uint) ([]models.User, error) {
+	query := s.db.Order("username")
+	if organizationID != 0 {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+
+	var users []models.User
+	err := query.Find(&users).Error
+	return users, translate(err)
+}
+
+func (s *Store) GetUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.First(&user, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (s *Store) FindUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (s *Store) CountUsers() (int64, error) {
+	var count int64
+	err := s.db.Model(&models.User{}).Count(&count).Error
+	return count, translate(err)
+}
+
+func (s *Store) CreateUser(user *models.User) error {
+	return translate(s.db.Create(user).Error)
+}
+
+func (s *Store) UpdatePassword(id uint, hash string) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{ID: id}).Update("password_hash", hash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("user_id = ?", id).Delete(&models.Session{}).Error
+	}))
+}
+
+func (s *Store) DeleteUser(id uint) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.RoleBinding{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListRoles(userID uint) ([]models.RoleBinding, error) {
+	var roles []models.RoleBinding
+	err := s.db.Where("user_id = ?", userID).Order("id").Find(&roles).Error
+	return roles, translate(err)
+}
+
+func (s *Store) ReplaceRoles(userID uint, roles []models.RoleBinding) error {
+	return translate(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RoleBinding{}).Error; err != nil {
+			return err
+		}
+		for i := range roles {
+			roles[i].ID = 0
+			roles[i].UserID = userID
+		}
+		if len(roles) == 0 {
+			return nil
+		}
+		return tx.Create(&roles).Error
+	}))
+}
+
+func (s *Store) CreateSession(session *models.Session) error {
+	return translate(s.db.Omit("User").Create(session).Error)
+}
+
+func (s *Store) GetSession(tokenHash string, now time.Time) (*models.Session, error) {
+	var session models.Session
+	err := s.db.Joins("User").
+		Where("sessions.token_hash = ? AND sessions.expires_at > ?", tokenHash, now).
+		First(&session).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (s *Store) DeleteSession(tokenHash string) error {
+	return translate(s.db.Where("token_hash = ?", tokenHash).Delete(&models.Session{}).Error)
+}
+
+func (s *Store) DeleteExpiredSessions(before time.Time) (int64, error) {
+	result := s.db.Where("expires_at <= ?", before).Delete(&models.Session{})
+	return result.RowsAffected, translate(result.Error)
+}
+
+func (s *Store) ListAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := s.db.Where("user_id = ?", userID).Order("id desc").Find(&keys).Error
+	return keys, translate(err)
+}
+
+func (s *Store) CreateAPIKey(key *models.APIKey) error {
+	return translate(s.db.Omit("User").Create(key).Error)
+}
+
+func (s *Store) FindAPIKey(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := s.db.Joins("User").Where("api_keys.key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &key, nil
+}
+
+func (s *Store) TouchAPIKey(id uint, at time.Time) error {
+	return translate(s.db.Model(&models.APIKey{ID: id}).Update("last_used_at", at).Error)
+}
+
+func (s *Store) DeleteAPIKey(userID, id uint) error {
+	result := s.db.Where("user_id = ?", userID).Delete(&models.APIKey{}, id)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translate(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
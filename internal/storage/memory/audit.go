@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) CreateAuditEntry(entry *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextAuditID++
+	entry.ID = s.nextAuditID
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	s.audit = append(s.audit, *entry)
+	return nil
+}
+
+func (s *Store) ListAuditEntries(filter storage.AuditFilter) ([]models.AuditEntry, int64, error) {
+	entries := s.matchingAudit(filter)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+
+	return paginate(entries, filter.Offset, filter.Limit), int64(len(entries)), nil
+}
+
+func (s *Store) StreamAuditEntries(filter storage.AuditFilter, fn func(models.AuditEntry) error) error {
+	entries := s.matchingAudit(filter)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cópia dos registros do filtro, para não segurar a trava durante fn
+func (s *Store) matchingAudit(filter storage.AuditFilter) []models.AuditEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := []models.AuditEntry{}
+	for _, entry := range s.audit {
+		switch {
+		case filter.OrganizationID != 0 && entry.OrganizationID != filter.OrganizationID,
+			filter.Username != "" && entry.Username != filter.Username,
+			filter.Action != "" && entry.Action != filter.Action,
+			filter.TargetType != "" && entry.TargetType != filter.TargetType,
+			filter.TargetID != 0 && entry.TargetID != filter.TargetID,
+			!filter.Since.IsZero() && entry.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !entry.CreatedAt.Before(filter.Until):
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) ListGroups(organizationID uint) ([]models.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := []models.Group{}
+	for _, group := range s.groups {
+		if group.OrganizationID == organizationID {
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+func (s *Store) GetGroup(id uint) (*models.Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	group, ok := s.groups[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &group, nil
+}
+
+func (s *Store) CreateGroup(group *models.Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if group.OrganizationID == 0 {
+		group.OrganizationID = models.DefaultOrganizationID
+	}
+	if s.groupNameTaken(group.OrganizationID, group.Name, 0) {
+		return storage.ErrDuplicate
+	}
+
+	s.nextGroupID++
+	now := time.Now()
+	group.ID = s.nextGroupID
+	group.CreatedAt = now
+	group.UpdatedAt = now
+	s.groups[group.ID] = *group
+	return nil
+}
+
+func (s *Store) UpdateGroup(group *models.Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.groups[group.ID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if s.groupNameTaken(current.OrganizationID, group.Name, group.ID) {
+		return storage.ErrDuplicate
+	}
+
+	current.Name = group.Name
+	current.Description = group.Description
+	current.UpdatedAt = time.Now()
+	s.groups[group.ID] = current
+	*group = current
+	return nil
+}
+
+func (s *Store) DeleteGroup(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[id]; !ok {
+		return storage.ErrNotFound
+	}
+	for _, site := range s.sites {
+		if site.GroupID != nil && *site.GroupID == id {
+			site.GroupID = nil
+		}
+	}
+	for userID, roles := range s.roles {
+		kept := roles[:0]
+		for _, role := range roles {
+			if role.GroupID == nil || *role.GroupID != id {
+				kept = append(kept, role)
+			}
+		}
+		s.roles[userID] = kept
+	}
+	delete(s.groups, id)
+	return nil
+}
+
+func (s *Store) groupNameTaken(organizationID uint, name string, except uint) bool {
+	for _, group := range s.groups {
+		if group.OrganizationID == organizationID && group.Name == name && group.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) SiteIDsByGroup(ids ...uint) ([]uint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	siteIDs := []uint{}
+	for _, site := range s.sites {
+		if !site.DeletedAt.Valid && site.GroupID != nil && containsID(ids, *site.GroupID) {
+			siteIDs = append(siteIDs, site.ID)
+		}
+	}
+	sort.Slice(siteIDs, func(i, j int) bool { return siteIDs[i] < siteIDs[j] })
+	return siteIDs, nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) OpenIncident(siteID uint) (*models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.incidents) - 1; i >= 0; i-- {
+		if s.incidents[i].SiteID == siteID && s.incidents[i].IsOpen() {
+			copied := s.incidents[i]
+			return &copied, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) CreateIncident(incident *models.Incident) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Como o índice único parcial do banco: um incidente aberto por site
+	if incident.IsOpen() {
+		for _, existing := range s.incidents {
+			if existing.SiteID == incident.SiteID && existing.IsOpen() {
+				return storage.ErrDuplicate
+			}
+		}
+	}
+
+	s.nextIncidentID++
+	now := time.Now()
+	incident.ID = s.nextIncidentID
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+	s.incidents = append(s.incidents, *incident)
+	return nil
+}
+
+func (s *Store) UpdateIncident(incident *models.Incident) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.incidents {
+		if s.incidents[i].ID == incident.ID {
+			incident.UpdatedAt = time.Now()
+			incident.AcknowledgedAt = s.incidents[i].AcknowledgedAt
+			incident.AcknowledgedBy = s.incidents[i].AcknowledgedBy
+			s.incidents[i] = *incident
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (s *Store) GetIncident(id uint) (*models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, incident := range s.incidents {
+		if incident.ID == id {
+			if site, ok := s.liveSite(incident.SiteID); ok {
+				incident.Site = *site
+			}
+			return &incident, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) AcknowledgeIncident(id uint, by string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.incidents {
+		if s.incidents[i].ID == id {
+			s.incidents[i].AcknowledgedAt = &at
+			s.incidents[i].AcknowledgedBy = by
+			s.incidents[i].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return storage.ErrNotFound
+}
+
+func (s *Store) ListIncidents(filter storage.IncidentFilter) ([]models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incidents := []models.Incident{}
+	for _, incident := range s.incidents {
+		if !s.inScope(incident.SiteID, storage.Scope{SiteID: filter.SiteID, OrganizationID: filter.OrganizationID}) {
+			continue
+		}
+		if filter.SiteIDs != nil && !containsID(filter.SiteIDs, incident.SiteID) {
+			continue
+		}
+		if filter.Open != nil && incident.IsOpen() != *filter.Open {
+			continue
+		}
+
+		if site, ok := s.liveSite(incident.SiteID); ok {
+			incident.Site = *site
+		}
+		incidents = append(incidents, incident)
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].StartedAt.After(incidents[j].StartedAt)
+	})
+
+	return paginate(incidents, 0, filter.Limit), nil
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) CreateLog(log *models.MonitorLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextLogID++
+	log.ID = s.nextLogID
+	log.CreatedAt = time.Now()
+	s.logs = append(s.logs, *log)
+	return nil
+}
+
+func (s *Store) LatestLog(siteID uint) (*models.MonitorLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *models.MonitorLog
+	for i := range s.logs {
+		if s.logs[i].SiteID == siteID && (latest == nil || s.logs[i].CheckedAt.After(latest.CheckedAt)) {
+			latest = &s.logs[i]
+		}
+	}
+	if latest == nil {
+		return nil, storage.ErrNotFound
+	}
+
+	copied := *latest
+	return &copied, nil
+}
+
+func (s *Store) ListLogs(filter storage.LogFilter) ([]models.MonitorLog, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []models.MonitorLog
+	for _, log := range s.logs {
+		if !s.matchLog(log, filter) {
+			continue
+		}
+
+		if site, ok := s.liveSite(log.SiteID); ok {
+			log.Site = *site
+		}
+		matched = append(matched, log)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].CheckedAt.Equal(matched[j].CheckedAt) {
+			return matched[i].CheckedAt.After(matched[j].CheckedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := int64(len(matched))
+	return paginate(matched, filter.Offset, filter.Limit), total, nil
+}
+
+func (s *Store) PageLogs(filter storage.LogFilter) ([]models.MonitorLog, error) {
+	filter.Offset = 0
+	logs, _, err := s.ListLogs(filter)
+	return logs, err
+}
+
+func (s *Store) StreamLogs(filter storage.LogFilter, fn func(models.MonitorLog) error) error {
+	// Copiar antes de chamar fn, que pode demorar (escrita na resposta HTTP)
+	s.mu.RLock()
+	var matched []models.MonitorLog
+	for _, log := range s.logs {
+		if s.matchLog(log, filter) {
+			matched = append(matched, log)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].CheckedAt.Equal(matched[j].CheckedAt) {
+			return matched[i].CheckedAt.Before(matched[j].CheckedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	for _, log := range matched {
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) matchLog(log models.MonitorLog, filter storage.LogFilter) bool {
+	if !s.inScope(log.SiteID, storage.Scope{SiteID: filter.SiteID, OrganizationID: filter.OrganizationID}) {
+		return false
+	}
+	if filter.SiteIDs != nil && !containsID(filter.SiteIDs, log.SiteID) {
+		return false
+	}
+	if !filter.Since.IsZero() && log.CheckedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !log.CheckedAt.Before(filter.Until) {
+		return false
+	}
+	if filter.Online != nil && log.IsOnline != *filter.Online {
+		return false
+	}
+	if len(filter.StatusCodes) > 0 {
+		matched := false
+		for _, r := range filter.StatusCodes {
+			if log.StatusCode >= r.Min && log.StatusCode <= r.Max {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if filter.MinLatency != nil && log.ResponseTime < *filter.MinLatency {
+		return false
+	}
+	if filter.MaxLatency != nil && log.ResponseTime > *filter.MaxLatency {
+		return false
+	}
+	if filter.ErrorText != "" && !strings.Contains(strings.ToLower(log.ErrorMessage), strings.ToLower(filter.ErrorText)) {
+		return false
+	}
+	if filter.CheckType != "" && log.CheckType != filter.CheckType {
+		return false
+	}
+	if before := filter.Before; before != nil {
+		if log.CheckedAt.After(before.CheckedAt) || (log.CheckedAt.Equal(before.CheckedAt) && log.ID >= before.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) FirstLogTime(since time.Time) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var earliest time.Time
+	for _, log := range s.logs {
+		if log.CheckedAt.Before(since) {
+			continue
+		}
+		if earliest.IsZero() || log.CheckedAt.Before(earliest) {
+			earliest = log.CheckedAt
+		}
+	}
+	return earliest, nil
+}
+
+func (s *Store) LogSamples(scope storage.Scope, from, to time.Time) ([]models.MonitorLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var samples []models.MonitorLog
+	for _, log := range s.logs {
+		if !s.inScope(log.SiteID, scope) {
+			continue
+		}
+		if !log.CheckedAt.Before(from) && log.CheckedAt.Before(to) {
+			samples = append(samples, log)
+		}
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		if samples[i].SiteID != samples[j].SiteID {
+			return samples[i].SiteID < samples[j].SiteID
+		}
+		return samples[i].CheckedAt.Before(samples[j].CheckedAt)
+	})
+	return samples, nil
+}
+
+func (s *Store) PurgeLogs(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.logs[:0]
+	for _, log := range s.logs {
+		if !log.CheckedAt.Before(before) {
+			kept = append(kept, log)
+		}
+	}
+
+	purged := int64(len(s.logs) - len(kept))
+	s.logs = kept
+	return purged, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Store implementa storage.Store em memória, para testes e para a CLI
+type Store struct {
+	mu        sync.RWMutex
+	sites     map[uint]*models.Site
+	logs      []models.MonitorLog
+	incidents []models.Incident
+	rollups   map[rollupKey]models.LogRollup
+	states    map[uint]models.CurrentState
+	tags      map[string]models.Tag
+	groups    map[uint]models.Group
+	users     map[uint]models.User
+	sessions  map[string]models.Session
+	apiKeys   map[uint]models.APIKey
+	roles     map[uint][]models.RoleBinding // Por usuário
+	orgs      map[uint]models.Organization
+	audit     []models.AuditEntry
+
+	nextSiteID     uint
+	nextLogID      uint
+	nextIncidentID uint
+	nextRollupID   uint
+	nextTagID      uint
+	nextGroupID    uint
+	nextUserID     uint
+	nextAPIKeyID   uint
+	nextOrgID      uint
+	nextAuditID    uint
+}
+
+var _ storage.Store = (*Store)(nil)
+
+// A organização padrão já existe, como depois das migrations
+func New() *Store {
+	now := time.Now()
+	return &Store{
+		sites:    make(map[uint]*models.Site),
+		rollups:  make(map[rollupKey]models.LogRollup),
+		states:   make(map[uint]models.CurrentState),
+		tags:     make(map[string]models.Tag),
+		groups:   make(map[uint]models.Group),
+		users:    make(map[uint]models.User),
+		sessions: make(map[string]models.Session),
+		apiKeys:  make(map[uint]models.APIKey),
+		roles:    make(map[uint][]models.RoleBinding),
+		orgs: map[uint]models.Organization{
+			models.DefaultOrganizationID: {ID: models.DefaultOrganizationID, Name: "Default", Slug: "default", CreatedAt: now, UpdatedAt: now},
+		},
+		nextOrgID: models.DefaultOrganizationID,
+	}
+}
+
+// Site não removido (soft delete), como o GORM retornaria
+func (s *Store) liveSite(id uint) (*models.Site, bool) {
+	site, ok := s.sites[id]
+	if !ok || site.DeletedAt.Valid {
+		return nil, false
+	}
+	return site, true
+}
+
+// Cópia do site com as tags e o grupo carregados, como o gormstore retorna
+func (s *Store) loaded(site *models.Site) models.Site {
+	copied := *site
+	copied.Tags = append([]models.Tag{}, site.Tags...)
+	copied.Group = nil
+	if site.GroupID != nil {
+		if group, ok := s.groups[*site.GroupID]; ok {
+			copied.Group = &group
+		}
+	}
+	return copied
+}
+
+// Se o log, incidente ou agregado do site entra no escopo. Sites removidos continuam
+// na organização
+func (s *Store) inScope(siteID uint, scope storage.Scope) bool {
+	if scope.SiteID != 0 && siteID != scope.SiteID {
+		return false
+	}
+	if scope.OrganizationID != 0 {
+		site, ok := s.sites[siteID]
+		return ok && site.OrganizationID == scope.OrganizationID
+	}
+	return true
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) ListOrganizations() ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for _, organization := range s.orgs {
+		organizations = append(organizations, organization)
+	}
+	sort.Slice(organizations, func(i, j int) bool { return organizations[i].ID < organizations[j].ID })
+	return organizations, nil
+}
+
+func (s *Store) GetOrganization(id uint) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organization, ok := s.orgs[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &organization, nil
+}
+
+func (s *Store) FindOrganizationBySlug(slug string) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, organization := range s.orgs {
+		if organization.Slug == slug {
+			return &organization, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) CreateOrganization(organization *models.Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.orgs {
+		if existing.Slug == organization.Slug {
+			return storage.ErrDuplicate
+		}
+	}
+
+	s.nextOrgID++
+	now := time.Now()
+	organization.ID = s.nextOrgID
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+	s.orgs[organization.ID] = *organization
+	return nil
+}
+
+func (s *Store) UpdateOrganization(organization *models.Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.orgs[organization.ID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	current.Name = organization.Name
+	current.MaxSites = organization.MaxSites
+	current.MinCheckIntervalSeconds = organization.MinCheckIntervalSeconds
+	current.UpdatedAt = time.Now()
+	s.orgs[current.ID] = current
+	*organization = current
+
+	// Os sites no intervalo padrão (0) também passam a usar o mínimo
+	if current.MinCheckIntervalSeconds == 0 {
+		return nil
+	}
+	for _, site := range s.sites {
+		if site.OrganizationID == current.ID && site.CheckIntervalSeconds < current.MinCheckIntervalSeconds {
+			site.CheckIntervalSeconds = current.MinCheckIntervalSeconds
+			site.Version++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+type rollupKey struct {
+	siteID      uint
+	period      string
+	bucketStart int64
+}
+
+func keyOf(rollup models.LogRollup) rollupKey {
+	return rollupKey{rollup.SiteID, rollup.Period, rollup.BucketStart.UnixNano()}
+}
+
+func (s *Store) SaveRollups(rollups []models.LogRollup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, rollup := range rollups {
+		key := keyOf(rollup)
+		if existing, ok := s.rollups[key]; ok {
+			rollup.ID = existing.ID
+			rollup.CreatedAt = existing.CreatedAt
+		} else {
+			s.nextRollupID++
+			rollup.ID = s.nextRollupID
+			rollup.CreatedAt = now
+		}
+		rollup.UpdatedAt = now
+		s.rollups[key] = rollup
+	}
+	return nil
+}
+
+func (s *Store) matchRollups(filter storage.RollupFilter) []models.LogRollup {
+	var matched []models.LogRollup
+	for _, rollup := range s.rollups {
+		if rollup.Period != filter.Period {
+			continue
+		}
+		if !s.inScope(rollup.SiteID, filter.Scope) {
+			continue
+		}
+		if !filter.Since.IsZero() && rollup.BucketStart.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !rollup.BucketStart.Before(filter.Until) {
+			continue
+		}
+		matched = append(matched, rollup)
+	}
+	return matched
+}
+
+func (s *Store) ListRollups(filter storage.RollupFilter) ([]models.LogRollup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rollups := s.matchRollups(filter)
+	sort.Slice(rollups, func(i, j int) bool {
+		if rollups[i].SiteID != rollups[j].SiteID {
+			return rollups[i].SiteID < rollups[j].SiteID
+		}
+		return rollups[i].BucketStart.Before(rollups[j].BucketStart)
+	})
+	return rollups, nil
+}
+
+func (s *Store) SumRollups(filter storage.RollupFilter) (*storage.RollupTotals, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var totals storage.RollupTotals
+	for _, rollup := range s.matchRollups(filter) {
+		totals.Checks.Total += rollup.Checks
+		totals.Checks.Online += rollup.Successes
+		totals.Durations.Up += time.Duration(rollup.UpTimeMs) * time.Millisecond
+		totals.Durations.Down += time.Duration(rollup.DownTimeMs) * time.Millisecond
+	}
+	return &totals, nil
+}
+
+func (s *Store) SumRollupsBySite(filter storage.RollupFilter) (map[uint]storage.RollupTotals, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := make(map[uint]storage.RollupTotals)
+	for _, rollup := range s.matchRollups(filter) {
+		total := totals[rollup.SiteID]
+		total.Checks.Total += rollup.Checks
+		total.Checks.Online += rollup.Successes
+		total.Durations.Up += time.Duration(rollup.UpTimeMs) * time.Millisecond
+		total.Durations.Down += time.Duration(rollup.DownTimeMs) * time.Millisecond
+		totals[rollup.SiteID] = total
+	}
+	return totals, nil
+}
+
+func (s *Store) LatestRollupBucket(period string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, rollup := range s.rollups {
+		if rollup.Period == period && rollup.BucketStart.After(latest) {
+			latest = rollup.BucketStart
+		}
+	}
+	return latest, nil
+}
+
+func (s *Store) FirstRollupBucket(period string, since time.Time) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var first time.Time
+	for _, rollup := range s.rollups {
+		if rollup.Period != period || rollup.BucketStart.Before(since) {
+			continue
+		}
+		if first.IsZero() || rollup.BucketStart.Before(first) {
+			first = rollup.BucketStart
+		}
+	}
+	return first, nil
+}
+
+func (s *Store) DeleteRollups(period string, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, rollup := range s.rollups {
+		if rollup.Period == period && rollup.BucketStart.Before(before) {
+			delete(s.rollups, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,344 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+	"gorm.io/gorm"
+)
+
+func (s *Store) ListSites(filter storage.SiteFilter) ([]models.Site, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sites := []models.Site{}
+	for _, site := range s.sites {
+		if site.DeletedAt.Valid || (filter.ActiveOnly && !site.Active) {
+			continue
+		}
+		if filter.OrganizationID != 0 && site.OrganizationID != filter.OrganizationID {
+			continue
+		}
+		sites = append(sites, s.loaded(site))
+	}
+
+	sort.Slice(sites, func(i, j int) bool { return sites[i].ID < sites[j].ID })
+	return sites, nil
+}
+
+func (s *Store) GetSite(id uint) (*models.Site, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	site, ok := s.liveSite(id)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	copied := s.loaded(site)
+	return &copied, nil
+}
+
+func (s *Store) CreateSite(site *models.Site) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// O índice único de URL também considera sites removidos, como no banco
+	if site.OrganizationID == 0 {
+		site.OrganizationID = models.DefaultOrganizationID
+	}
+	if s.urlTaken(site.OrganizationID, site.URL, 0) {
+		return storage.ErrDuplicate
+	}
+
+	s.nextSiteID++
+	now := time.Now()
+	site.ID = s.nextSiteID
+	site.Version = 1
+	site.CreatedAt = now
+	site.UpdatedAt = now
+	site.Tags = s.saveTags(site.Tags)
+
+	copied := *site
+	copied.Group = nil
+	s.sites[site.ID] = &copied
+	return nil
+}
+
+func (s *Store) urlTaken(organizationID uint, url string, except uint) bool {
+	for _, existing := range s.sites {
+		if existing.ID != except && existing.OrganizationID == organizationID && existing.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) FindSiteByURL(organizationID uint, url string) (*models.Site, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, site := range s.sites {
+		if site.OrganizationID == organizationID && site.URL == url {
+			copied := s.loaded(site)
+			return &copied, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) CountSites(organizationID uint) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, site := range s.sites {
+		if !site.DeletedAt.Valid && site.OrganizationID == organizationID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Store) CheckIntervals() (map[uint]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	intervals := make(map[uint]int)
+	for _, site := range s.sites {
+		if seconds := max(site.CheckIntervalSeconds, s.orgs[site.OrganizationID].MinCheckIntervalSeconds); seconds > 0 {
+			intervals[site.ID] = seconds
+		}
+	}
+	return intervals, nil
+}
+
+func (s *Store) UpdateSite(site *models.Site) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.liveSite(site.ID)
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if current.Version != site.Version {
+		return storage.ErrConflict
+	}
+	if s.urlTaken(site.OrganizationID, site.URL, site.ID) {
+		return storage.ErrDuplicate
+	}
+
+	site.Version++
+	site.UpdatedAt = time.Now()
+	if site.Tags == nil {
+		site.Tags = current.Tags
+	}
+	site.Tags = s.saveTags(site.Tags)
+
+	copied := *site
+	copied.Group = nil
+	s.sites[site.ID] = &copied
+	return nil
+}
+
+func (s *Store) DeleteSite(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	site, ok := s.liveSite(id)
+	if !ok {
+		return storage.ErrNotFound
+	}
+	site.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (s *Store) SearchSites(search storage.SiteSearch) ([]models.Site, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := strings.ToLower(search.Query)
+	sites := []models.Site{}
+	for _, site := range s.sites {
+		if site.DeletedAt.Valid {
+			continue
+		}
+		if search.OrganizationID != 0 && site.OrganizationID != search.OrganizationID {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(site.Name), query) &&
+			!strings.Contains(strings.ToLower(site.URL), query) {
+			continue
+		}
+		if search.Tag != "" && !hasTag(*site, search.Tag) {
+			continue
+		}
+		if search.GroupID != 0 && (site.GroupID == nil || *site.GroupID != search.GroupID) {
+			continue
+		}
+		if search.SiteIDs != nil && !containsID(search.SiteIDs, site.ID) {
+			continue
+		}
+		if search.Public && !site.Public {
+			continue
+		}
+
+		copied := s.loaded(site)
+		if state, ok := s.states[site.ID]; ok {
+			copied.State = &state
+		}
+		sites = append(sites, copied)
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		return lessSite(sites[i], sites[j], search.Sort, search.Desc)
+	})
+	return paginate(sites, search.Offset, search.Limit), int64(len(sites)), nil
+}
+
+// Mesma ordem do gormstore: valores ausentes sempre no fim, desempate pelo id
+func lessSite(a, b models.Site, by string, desc bool) bool {
+	less := func(x, y bool) bool {
+		if desc {
+			return y
+		}
+		return x
+	}
+
+	var av, bv *float64
+	switch by {
+	case storage.SortByName:
+		if a.Name != b.Name {
+			return less(a.Name < b.Name, a.Name > b.Name)
+		}
+		return less(a.ID < b.ID, a.ID > b.ID)
+	case storage.SortByStatus:
+		av, bv = statusRank(a), statusRank(b)
+	case storage.SortByUptime:
+		av, bv = stateValue(a, func(s models.CurrentState) *float64 { return s.Uptime24h }),
+			stateValue(b, func(s models.CurrentState) *float64 { return s.Uptime24h })
+	case storage.SortByLatency:
+		latency := func(s models.CurrentState) *float64 {
+			value := float64(s.ResponseTime)
+			return &value
+		}
+		av, bv = stateValue(a, latency), stateValue(b, latency)
+	default:
+		return less(a.ID < b.ID, a.ID > b.ID)
+	}
+
+	switch {
+	case av == nil && bv == nil:
+	case av == nil:
+		return false
+	case bv == nil:
+		return true
+	case *av != *bv:
+		return less(*av < *bv, *av > *bv)
+	}
+	return less(a.ID < b.ID, a.ID > b.ID)
+}
+
+func stateValue(site models.Site, value func(models.CurrentState) *float64) *float64 {
+	if site.State == nil {
+		return nil
+	}
+	return value(*site.State)
+}
+
+func statusRank(site models.Site) *float64 {
+	if site.State == nil || !site.Active {
+		return nil
+	}
+	rank := 2.0
+	switch site.State.Status {
+	case "down":
+		rank = 0
+	case "degraded":
+		rank = 1
+	}
+	return &rank
+}
+
+func (s *Store) ListDeletedSites(organizationID uint, before time.Time) ([]models.Site, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sites := []models.Site{}
+	for _, site := range s.sites {
+		if !site.DeletedAt.Valid || (organizationID != 0 && site.OrganizationID != organizationID) {
+			continue
+		}
+		if !before.IsZero() && !site.DeletedAt.Time.Before(before) {
+			continue
+		}
+		sites = append(sites, s.loaded(site))
+	}
+
+	sort.Slice(sites, func(i, j int) bool { return sites[i].DeletedAt.Time.After(sites[j].DeletedAt.Time) })
+	return sites, nil
+}
+
+func (s *Store) GetDeletedSite(organizationID, id uint) (*models.Site, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	site, ok := s.sites[id]
+	if !ok || !site.DeletedAt.Valid || site.OrganizationID != organizationID {
+		return nil, storage.ErrNotFound
+	}
+	copied := s.loaded(site)
+	return &copied, nil
+}
+
+func (s *Store) RestoreSite(id uint) (*models.Site, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	site, ok := s.sites[id]
+	if !ok || !site.DeletedAt.Valid {
+		return nil, storage.ErrNotFound
+	}
+	site.DeletedAt = gorm.DeletedAt{}
+	site.Version++
+	site.UpdatedAt = time.Now()
+
+	copied := s.loaded(site)
+	return &copied, nil
+}
+
+func (s *Store) PurgeSite(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sites[id]; !ok {
+		return storage.ErrNotFound
+	}
+
+	logs := s.logs[:0]
+	for _, log := range s.logs {
+		if log.SiteID != id {
+			logs = append(logs, log)
+		}
+	}
+	s.logs = logs
+
+	incidents := s.incidents[:0]
+	for _, incident := range s.incidents {
+		if incident.SiteID != id {
+			incidents = append(incidents, incident)
+		}
+	}
+	s.incidents = incidents
+
+	for key := range s.rollups {
+		if key.siteID == id {
+			delete(s.rollups, key)
+		}
+	}
+	delete(s.states, id)
+	delete(s.sites, id)
+	return nil
+}
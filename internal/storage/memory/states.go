@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) GetCurrentState(siteID uint) (*models.CurrentState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, ok := s.states[siteID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &state, nil
+}
+
+func (s *Store) SaveCurrentState(state *models.CurrentState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Os uptimes em cache só mudam por SaveUptimes
+	if existing, ok := s.states[state.SiteID]; ok {
+		state.SiteUptimes = existing.SiteUptimes
+		state.UptimeUpdatedAt = existing.UptimeUpdatedAt
+	}
+	state.UpdatedAt = time.Now()
+	s.states[state.SiteID] = *state
+	return nil
+}
+
+func (s *Store) SaveUptimes(uptimes map[uint]models.SiteUptimes, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for siteID, uptime := range uptimes {
+		state, ok := s.states[siteID]
+		if !ok {
+			continue
+		}
+		state.SiteUptimes = uptime
+		updatedAt := at
+		state.UptimeUpdatedAt = &updatedAt
+		s.states[siteID] = state
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) SiteStatusCounts(organizationID uint) (*storage.StatusCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var counts storage.StatusCounts
+
+	// Último log de cada site
+	latest := make(map[uint]int)
+	for i, log := range s.logs {
+		if last, ok := latest[log.SiteID]; !ok || log.CheckedAt.After(s.logs[last].CheckedAt) {
+			latest[log.SiteID] = i
+		}
+	}
+
+	for _, site := range s.sites {
+		if site.DeletedAt.Valid || !site.Active {
+			continue
+		}
+		if organizationID != 0 && site.OrganizationID != organizationID {
+			continue
+		}
+		counts.Total++
+
+		if last, ok := latest[site.ID]; ok {
+			if s.logs[last].IsOnline {
+				counts.Online++
+			} else {
+				counts.Offline++
+			}
+		}
+	}
+
+	return &counts, nil
+}
+
+func (s *Store) CountChecks(scope storage.Scope, from, to time.Time) (*storage.CheckCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var counts storage.CheckCounts
+	for _, log := range s.logs {
+		if !s.inScope(log.SiteID, scope) {
+			continue
+		}
+		if log.CheckedAt.Before(from) || !log.CheckedAt.Before(to) {
+			continue
+		}
+
+		counts.Total++
+		if log.IsOnline {
+			counts.Online++
+		}
+	}
+
+	return &counts, nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+// Atribuir IDs às tags pelo nome, criando as que não existem (chamar com o lock de escrita)
+func (s *Store) saveTags(tags []models.Tag) []models.Tag {
+	saved := make([]models.Tag, 0, len(tags))
+	for _, tag := range tags {
+		existing, ok := s.tags[tag.Name]
+		if !ok {
+			s.nextTagID++
+			existing = models.Tag{ID: s.nextTagID, Name: tag.Name}
+			s.tags[tag.Name] = existing
+		}
+		saved = append(saved, existing)
+	}
+
+	sort.Slice(saved, func(i, j int) bool { return saved[i].Name < saved[j].Name })
+	return saved
+}
+
+func hasTag(site models.Site, names ...string) bool {
+	for _, tag := range site.Tags {
+		for _, name := range names {
+			if tag.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Store) ListTags(organizationID uint) ([]models.TagCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, site := range s.sites {
+		if site.DeletedAt.Valid || site.OrganizationID != organizationID {
+			continue
+		}
+		for _, tag := range site.Tags {
+			counts[tag.Name]++
+		}
+	}
+
+	tags := []models.TagCount{}
+	for name, count := range counts {
+		tags = append(tags, models.TagCount{Name: name, Sites: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// A tag sai do mapa quando nenhum site de outra organização a usa
+func (s *Store) DeleteTag(organizationID uint, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed, remaining := false, false
+	for _, site := range s.sites {
+		if !hasTag(*site, name) {
+			continue
+		}
+		if site.OrganizationID != organizationID {
+			remaining = true
+			continue
+		}
+
+		tags := site.Tags[:0]
+		for _, tag := range site.Tags {
+			if tag.Name != name {
+				tags = append(tags, tag)
+			}
+		}
+		site.Tags = tags
+		removed = true
+	}
+	if !removed {
+		return storage.ErrNotFound
+	}
+	if !remaining {
+		delete(s.tags, name)
+	}
+	return nil
+}
+
+func (s *Store) SiteIDsByTag(organizationID uint, names ...string) ([]uint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []uint{}
+	for _, site := range s.sites {
+		if !site.DeletedAt.Valid && site.OrganizationID == organizationID && hasTag(*site, names...) {
+			ids = append(ids, site.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
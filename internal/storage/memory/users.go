@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+	"github.com/luacarol/website-monitoring/internal/storage"
+)
+
+func (s *Store) ListUsers(organizationID uint) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range s.users {
+		if organizationID == 0 || user.OrganizationID == organizationID {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+func (s *Store) GetUser(id uint) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (s *Store) FindUserByUsername(username string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) CountUsers() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(len(s.users)), nil
+}
+
+func (s *Store) CreateUser(user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.users {
+		if existing.Username == user.Username {
+			return storage.ErrDuplicate
+		}
+	}
+
+	if user.OrganizationID == 0 {
+		user.OrganizationID = models.DefaultOrganizationID
+	}
+	s.nextUserID++
+	now := time.Now()
+	user.ID = s.nextUserID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	s.users[user.ID] = *user
+	return nil
+}
+
+func (s *Store) UpdatePassword(id uint, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	user.PasswordHash = hash
+	user.UpdatedAt = time.Now()
+	s.users[id] = user
+	for tokenHash, session := range s.sessions {
+		if session.UserID == id {
+			delete(s.sessions, tokenHash)
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteUser(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; !ok {
+		return storage.ErrNotFound
+	}
+	for hash, session := range s.sessions {
+		if session.UserID == id {
+			delete(s.sessions, hash)
+		}
+	}
+	for keyID, key := range s.apiKeys {
+		if key.UserID == id {
+			delete(s.apiKeys, keyID)
+		}
+	}
+	delete(s.roles, id)
+	delete(s.users, id)
+	return nil
+}
+
+func (s *Store) ListRoles(userID uint) ([]models.RoleBinding, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.RoleBinding{}, s.roles[userID]...), nil
+}
+
+func (s *Store) ReplaceRoles(userID uint, roles []models.RoleBinding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	stored := make([]models.RoleBinding, 0, len(roles))
+	for _, role := range roles {
+		role.UserID = userID
+		role.CreatedAt = now
+		stored = append(stored, role)
+	}
+	s.roles[userID] = stored
+	return nil
+}
+
+func (s *Store) CreateSession(session *models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.CreatedAt = time.Now()
+	stored := *session
+	stored.User = models.User{}
+	s.sessions[session.TokenHash] = stored
+	return nil
+}
+
+func (s *Store) GetSession(tokenHash string, now time.Time) (*models.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[tokenHash]
+	if !ok || !session.ExpiresAt.After(now) {
+		return nil, storage.ErrNotFound
+	}
+	user, ok := s.users[session.UserID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	session.User = user
+	return &session, nil
+}
+
+func (s *Store) DeleteSession(tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, tokenHash)
+	return nil
+}
+
+func (s *Store) DeleteExpiredSessions(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for hash, session := range s.sessions {
+		if !session.ExpiresAt.After(before) {
+			delete(s.sessions, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) ListAPIKeys(userID uint) ([]models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := []models.APIKey{}
+	for _, key := range s.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID > keys[j].ID })
+	return keys, nil
+}
+
+func (s *Store) CreateAPIKey(key *models.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.apiKeys {
+		if existing.KeyHash == key.KeyHash {
+			return storage.ErrDuplicate
+		}
+	}
+
+	s.nextAPIKeyID++
+	key.ID = s.nextAPIKeyID
+	key.CreatedAt = time.Now()
+	stored := *key
+	stored.User = models.User{}
+	s.apiKeys[key.ID] = stored
+	return nil
+}
+
+func (s *Store) FindAPIKey(keyHash string) (*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.apiKeys {
+		if key.KeyHash != keyHash {
+			continue
+		}
+		user, ok := s.users[key.UserID]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		key.User = user
+		return &key, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) TouchAPIKey(id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		s.apiKeys[id] = key
+	}
+	return nil
+}
+
+func (s *Store) DeleteAPIKey(userID, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.apiKeys[id]
+	if !ok || key.UserID != userID {
+		return storage.ErrNotFound
+	}
+	delete(s.apiKeys, id)
+	return nil
+}
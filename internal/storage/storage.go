@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/luacarol/website-monitoring/internal/models"
+)
+
+var (
+	ErrNotFound  = errors.New("registro não encontrado")
+	ErrDuplicate = errors.New("registro duplicado")
+	// O registro foi alterado por outra operação desde a leitura
+	ErrConflict = errors.New("registro alterado por outra operação")
+)
+
+// Store reúne todas as operações de persistência usadas pelos handlers e pelo monitor
+type Store interface {
+	SiteRepository
+	LogRepository
+	IncidentRepository
+	StatsRepository
+	RollupRepository
+	StateRepository
+	TagRepository
+	GroupRepository
+	UserRepository
+	OrganizationRepository
+	AuditRepository
+}
+
+// Sites de uma consulta agregada: um site, os sites de uma organização ou todos (zero),
+// incluindo os logs e agregados dos sites removidos
+type Scope struct {
+	SiteID         uint
+	OrganizationID uint
+}
+
+type SiteFilter struct {
+	OrganizationID uint // 0 para todas as organizações (monitor e tarefas de fundo)
+	ActiveOnly     bool
+}
+
+// Ordenações da listagem de sites
+const (
+	SortByID      = ""
+	SortByName    = "name"
+	SortByStatus  = "status"
+	SortByUptime  = "uptime"
+	SortByLatency = "latency"
+)
+
+// Listagem paginada de sites com o estado atual
+type SiteSearch struct {
+	OrganizationID uint
+	Query          string // Trecho do nome ou da URL, sem diferenciar maiúsculas
+	Tag            string
+	GroupID        uint
+	SiteIDs        []uint // Restringir a estes sites (nil para não filtrar)
+	Public         bool   // Apenas os sites da página de status
+	Sort           string
+	Desc           bool
+	Offset         int
+	Limit          int // 0 para todos
+}
+
+// Os sites são retornados com Tags e Group carregados. CreateSite e UpdateSite gravam as tags
+// pelo nome, criando as que ainda não existem
+type SiteRepository interface {
+	ListSites(filter SiteFilter) ([]models.Site, error)
+	// Sites com State carregado (nil sem checks) e o total sem paginação
+	SearchSites(search SiteSearch) ([]models.Site, int64, error)
+	GetSite(id uint) (*models.Site, error)
+	// Site da organização com a URL, incluindo os removidos (soft delete), ou ErrNotFound
+	FindSiteByURL(organizationID uint, url string) (*models.Site, error)
+	// Sites não removidos da organização
+	CountSites(organizationID uint) (int64, error)
+	// Intervalo dos sites, incluindo os removidos, que não usam o padrão: o do site ou o
+	// mínimo da organização, o maior
+	CheckIntervals() (map[uint]int, error)
+	CreateSite(site *models.Site) error
+	// Salvar se a versão não mudou desde a leitura (ErrConflict) e incrementá-la
+	UpdateSite(site *models.Site) error
+	DeleteSite(id uint) error
+	// Sites da organização (0 para todas) removidos (soft delete) antes de before (zero para
+	// todos), mais recentes primeiro
+	ListDeletedSites(organizationID uint, before time.Time) ([]models.Site, error)
+	// Site da lixeira da organização; ErrNotFound se não estiver na lixeira
+	GetDeletedSite(organizationID, id uint) (*models.Site, error)
+	// Desfazer a remoção; ErrNotFound se o site não estiver na lixeira
+	RestoreSite(id uint) (*models.Site, error)
+	// Remover definitivamente o site com logs, incidentes, agregados e estado
+	PurgeSite(id uint) error
+}
+
+// Filtros já validados de models.LogsQuery
+type LogFilter struct {
+	OrganizationID uint
+	SiteID         uint
+	SiteIDs        []uint    // Restringir a estes sites (nil para não filtrar)
+	Since          time.Time // Inclusivo
+	Until          time.Time // Exclusivo
+	Online         *bool
+	StatusCodes    []CodeRange // Qualquer uma das faixas
+	MinLatency     *int64      // Tempo de resposta em ms, inclusivo
+	MaxLatency     *int64
+	ErrorText      string // Trecho da mensagem de erro, sem diferenciar maiúsculas
+	CheckType      string
+	Before         *LogCursor // Paginação por cursor: apenas logs anteriores ao cursor
+	Offset         int
+	Limit          int
+}
+
+// Faixa inclusiva de status codes
+type CodeRange struct {
+	Min, Max int
+}
+
+// Posição de um log na ordem (checked_at, id)
+type LogCursor struct {
+	CheckedAt time.Time
+	ID        uint
+}
+
+type LogRepository interface {
+	CreateLog(log *models.MonitorLog) error
+	LatestLog(siteID uint) (*models.MonitorLog, error)
+	// Logs mais recentes primeiro, com o site carregado, e o total sem paginação
+	ListLogs(filter LogFilter) ([]models.MonitorLog, int64, error)
+	// Como ListLogs, sem o total e sem Offset (paginação por cursor)
+	PageLogs(filter LogFilter) ([]models.MonitorLog, error)
+	// Percorrer os logs do filtro do mais antigo ao mais recente (por horário e ID), um por vez,
+	// sem carregar o resultado inteiro nem o site. Offset e Limit são ignorados; um erro de fn
+	// interrompe a leitura e é retornado
+	StreamLogs(filter LogFilter, fn func(models.MonitorLog) error) error
+	// Horário do primeiro log a partir de since (zero sem logs)
+	FirstLogTime(since time.Time) (time.Time, error)
+	// Logs do escopo em [from, to), ordenados por site e horário, sem o site carregado
+	LogSamples(scope Scope, from, to time.Time) ([]models.MonitorLog, error)
+	// Remover definitivamente os logs anteriores a before
+	PurgeLogs(before time.Time) (int64, error)
+}
+
+type IncidentFilter struct {
+	OrganizationID uint
+	SiteID         uint
+	SiteIDs        []uint // Restringir a estes sites (nil para não filtrar)
+	Open           *bool
+	Limit          int
+}
+
+type IncidentRepository interface {
+	// Incidente aberto do site ou ErrNotFound
+	OpenIncident(siteID uint) (*models.Incident, error)
+	// ErrDuplicate se o site já tiver um incidente aberto
+	CreateIncident(incident *models.Incident) error
+	// Salvar o incidente sem alterar o reconhecimento
+	UpdateIncident(incident *models.Incident) error
+	// Incidente com o site carregado, ou ErrNotFound
+	GetIncident(id uint) (*models.Incident, error)
+	AcknowledgeIncident(id uint, by string, at time.Time) error
+	// Incidentes mais recentes primeiro, com o site carregado
+	ListIncidents(filter IncidentFilter) ([]models.Incident, error)
+}
+
+// Sites ativos e quantos estão online/offline pelo último check
+type StatusCounts struct {
+	Total   int64
+	Online  int64
+	Offline int64
+}
+
+// Quantidade de checks e quantos foram online
+type CheckCounts struct {
+	Total  int64
+	Online int64
+}
+
+func (c CheckCounts) Add(other CheckCounts) CheckCounts {
+	return CheckCounts{Total: c.Total + other.Total, Online: c.Online + other.Online}
+}
+
+// Percentual de checks online (0 sem dados)
+func (c CheckCounts) Uptime() float64 {
+	if c.Total == 0 {
+		return 0.0
+	}
+	return (float64(c.Online) / float64(c.Total)) * 100
+}
+
+// Tempo que os sites passaram online e offline
+type StateDurations struct {
+	Up   time.Duration
+	Down time.Duration
+}
+
+func (d StateDurations) Add(other StateDurations) StateDurations {
+	return StateDurations{Up: d.Up + other.Up, Down: d.Down + other.Down}
+}
+
+// Tempo com estado conhecido
+func (d StateDurations) Known() time.Duration {
+	return d.Up + d.Down
+}
+
+// Percentual do tempo conhecido em que o site esteve online (0 sem dados)
+func (d StateDurations) Uptime() float64 {
+	if d.Known() <= 0 {
+		return 0.0
+	}
+	return (float64(d.Up) / float64(d.Known())) * 100
+}
+
+// Somas dos agregados
+type RollupTotals struct {
+	Checks    CheckCounts
+	Durations StateDurations
+}
+
+// As tags são compartilhadas pelo nome entre as organizações; as operações consideram apenas
+// os sites da organização
+type TagRepository interface {
+	// Tags em uso, com o número de sites não removidos
+	ListTags(organizationID uint) ([]models.TagCount, error)
+	// Remover a tag dos sites da organização; ErrNotFound se nenhum deles a tiver
+	DeleteTag(organizationID uint, name string) error
+	// Sites não removidos com qualquer uma das tags. Base para tudo que é direcionado por tag:
+	// filtros da API, janelas de manutenção e roteamento de notificações
+	SiteIDsByTag(organizationID uint, names ...string) ([]uint, error)
+}
+
+type GroupRepository interface {
+	ListGroups(organizationID uint) ([]models.Group, error)
+	GetGroup(id uint) (*models.Group, error)
+	// ErrDuplicate se o nome já existir na organização
+	CreateGroup(group *models.Group) error
+	UpdateGroup(group *models.Group) error
+	// Os sites do grupo ficam sem grupo e os papéis no grupo são removidos
+	DeleteGroup(id uint) error
+	// Sites não removidos dos grupos
+	SiteIDsByGroup(ids ...uint) ([]uint, error)
+}
+
+type UserRepository interface {
+	// Usuários da organização (0 para todas)
+	ListUsers(organizationID uint) ([]models.User, error)
+	GetUser(id uint) (*models.User, error)
+	// Usuário pelo nome ou ErrNotFound
+	FindUserByUsername(username string) (*models.User, error)
+	CountUsers() (int64, error)
+	// ErrDuplicate se o nome já existir
+	CreateUser(user *models.User) error
+	// Trocar a senha e encerrar todas as sessões do usuário
+	UpdatePassword(id uint, hash string) error
+	// Remover o usuário com as sessões, as chaves de API e os papéis
+	DeleteUser(id uint) error
+
+	ListRoles(userID uint) ([]models.RoleBinding, error)
+	// Substituir todos os papéis do usuário
+	ReplaceRoles(userID uint, roles []models.RoleBinding) error
+
+	CreateSession(session *models.Session) error
+	// Sessão válida em now pelo hash do token, com o usuário carregado, ou ErrNotFound
+	GetSession(tokenHash string, now time.Time) (*models.Session, error)
+	DeleteSession(tokenHash string) error
+	// Remover as sessões expiradas antes de before
+	DeleteExpiredSessions(before time.Time) (int64, error)
+
+	// Chaves do usuário, mais recentes primeiro
+	ListAPIKeys(userID uint) ([]models.APIKey, error)
+	CreateAPIKey(key *models.APIKey) error
+	// Chave pelo hash, com o usuário carregado, ou ErrNotFound
+	FindAPIKey(keyHash string) (*models.APIKey, error)
+	TouchAPIKey(id uint, at time.Time) error
+	// ErrNotFound se a chave não existir ou for de outro usuário
+	DeleteAPIKey(userID, id uint) error
+}
+
+type StatsRepository interface {
+	// Sites da organização (0 para todas)
+	SiteStatusCounts(organizationID uint) (*StatusCounts, error)
+	// Checks brutos do escopo em [from, to)
+	CountChecks(scope Scope, from, to time.Time) (*CheckCounts, error)
+}
+
+type RollupFilter struct {
+	Scope
+	Period string
+	Since  time.Time // Inclusivo, pelo início do bucket
+	Until  time.Time // Exclusivo, pelo início do bucket
+}
+
+type RollupRepository interface {
+	// Inserir ou substituir agregados pela chave (site, período, bucket)
+	SaveRollups(rollups []models.LogRollup) error
+	// Agregados ordenados por site e bucket
+	ListRollups(filter RollupFilter) ([]models.LogRollup, error)
+	// Soma de checks, sucessos e tempo em cada estado dos agregados
+	SumRollups(filter RollupFilter) (*RollupTotals, error)
+	// Mesma soma, separada por site
+	SumRollupsBySite(filter RollupFilter) (map[uint]RollupTotals, error)
+	// Início do bucket mais recente do período (zero sem agregados)
+	LatestRollupBucket(period string) (time.Time, error)
+	// Início do primeiro bucket do período a partir de since (zero sem agregados)
+	FirstRollupBucket(period string, since time.Time) (time.Time, error)
+	DeleteRollups(period string, before time.Time) (int64, error)
+}
+
+type StateRepository interface {
+	// Estado atual do site ou ErrNotFound
+	GetCurrentState(siteID uint) (*models.CurrentState, error)
+	// Inserir ou atualizar os dados do último check, sem alterar os uptimes
+	SaveCurrentState(state *models.CurrentState) error
+	// Atualizar os uptimes em cache dos sites que já têm estado
+	SaveUptimes(uptimes map[uint]models.SiteUptimes, at time.Time) error
+}
+
+type OrganizationRepository interface {
+	ListOrganizations() ([]models.Organization, error)
+	GetOrganization(id uint) (*models.Organization, error)
+	// Organização pelo slug ou ErrNotFound
+	FindOrganizationBySlug(slug string) (*models.Organization, error)
+	// ErrDuplicate se o slug já existir
+	CreateOrganization(organization *models.Organization) error
+	// Salvar o nome e as cotas e subir para o novo intervalo mínimo os sites abaixo dele
+	UpdateOrganization(organization *models.Organization) error
+}
+
+type AuditFilter struct {
+	OrganizationID uint
+	Username       string
+	Action         string
+	TargetType     string
+	TargetID       uint
+	Since          time.Time // Inclusivo
+	Until          time.Time // Exclusivo
+	Offset         int
+	Limit          int // 0 para todos
+}
+
+type AuditRepository interface {
+	CreateAuditEntry(entry *models.AuditEntry) error
+	// Registros mais recentes primeiro e o total sem paginação
+	ListAuditEntries(filter AuditFilter) ([]models.AuditEntry, int64, error)
+	// Chamar fn para cada registro, do mais antigo ao mais recente, lendo com um cursor
+	StreamAuditEntries(filter AuditFilter, fn func(models.AuditEntry) error) error
+}
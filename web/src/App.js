@@ -7,22 +7,60 @@ import {
   FileText, 
   Plus,
   Wifi,
-  WifiOff
+  WifiOff,
+  LogOut,
+  RefreshCw
 } from 'lucide-react';
 
 import Dashboard from './components/Dashboard';
 import SitesManager from './components/SitesManager';
 import LogsViewer from './components/LogsViewer';
-import { monitorAPI } from './services/api';
+import Login from './components/Login';
+import { authAPI, monitorAPI, onUnauthorized } from './services/api';
+
+const toasterOptions = {
+  duration: 4000,
+  style: {
+    background: '#363636',
+    color: '#fff',
+  },
+  success: {
+    duration: 3000,
+  },
+  error: {
+    duration: 5000,
+  },
+};
 
 function App() {
   const [monitorStatus, setMonitorStatus] = useState({ running: false });
+  // undefined enquanto a sessão é verificada, null sem login
+  const [user, setUser] = useState(undefined);
 
   useEffect(() => {
+    onUnauthorized(() => setUser(null));
+    authAPI.me()
+      .then((response) => setUser(response.data.user))
+      .catch(() => setUser(null));
+  }, []);
+
+  useEffect(() => {
+    if (!user) {
+      return undefined;
+    }
     checkMonitorStatus();
     const interval = setInterval(checkMonitorStatus, 30000);
     return () => clearInterval(interval);
-  }, []);
+  }, [user]);
+
+  const handleLogout = async () => {
+    try {
+      await authAPI.logout();
+    } catch (error) {
+      console.error('Erro ao encerrar sessão:', error);
+    }
+    setUser(null);
+  };
 
   const checkMonitorStatus = async () => {
     try {
@@ -33,25 +71,27 @@ function App() {
     }
   };
 
+  if (user === undefined) {
+    return (
+      <div className="min-h-screen bg-gray-50 flex items-center justify-center">
+        <RefreshCw className="h-8 w-8 animate-spin text-primary-500" />
+      </div>
+    );
+  }
+
+  if (user === null) {
+    return (
+      <div className="min-h-screen bg-gray-50">
+        <Toaster position="top-right" toastOptions={toasterOptions} />
+        <Login onLogin={setUser} />
+      </div>
+    );
+  }
+
   return (
     <Router>
       <div className="min-h-screen bg-gray-50">
-        <Toaster
-          position="top-right"
-          toastOptions={{
-            duration: 4000,
-            style: {
-              background: '#363636',
-              color: '#fff',
-            },
-            success: {
-              duration: 3000,
-            },
-            error: {
-              duration: 5000,
-            },
-          }}
-        />
+        <Toaster position="top-right" toastOptions={toasterOptions} />
         
         {/* Header */}
         <header className="bg-white border-b border-gray-200 sticky top-0 z-40">
@@ -73,7 +113,7 @@ function App() {
               </div>
 
               {/* Monitor Status */}
-              <div className="flex items-center space-x-2">
+              <div className="flex items-center space-x-4">
                 <div className="flex items-center space-x-1">
                   {monitorStatus.running ? (
                     <>
@@ -93,6 +133,18 @@ function App() {
                     </>
                   )}
                 </div>
+
+                {/* Current User */}
+                <div className="flex items-center space-x-2 border-l border-gray-200 pl-4">
+                  <span className="text-sm text-gray-600">{user.username}</span>
+                  <button
+                    onClick={handleLogout}
+                    className="text-gray-400 hover:text-gray-600"
+                    title="Sign out"
+                  >
+                    <LogOut className="h-4 w-4" />
+                  </button>
+                </div>
               </div>
             </div>
           </div>
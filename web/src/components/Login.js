@@ -0,0 +1,96 @@
+import React, { useState } from 'react';
+import { Monitor, LogIn, RefreshCw } from 'lucide-react';
+import toast from 'react-hot-toast';
+
+import { authAPI } from '../services/api';
+
+const Login = ({ onLogin }) => {
+  const [formData, setFormData] = useState({ username: '', password: '' });
+  const [submitting, setSubmitting] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setSubmitting(true);
+    try {
+      const response = await authAPI.login(formData.username, formData.password);
+      onLogin(response.data.user);
+    } catch (error) {
+      const errorMsg = error.response?.data?.error || 'Erro ao entrar';
+      toast.error(errorMsg);
+      setFormData(prev => ({ ...prev, password: '' }));
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center px-4">
+      <div className="card w-full max-w-sm">
+        <form onSubmit={handleSubmit} className="space-y-4">
+          <div className="flex items-center space-x-3">
+            <div className="bg-blue-600 p-2 rounded-lg">
+              <Monitor className="h-6 w-6 text-white" />
+            </div>
+            <div>
+              <h1 className="text-xl font-semibold text-gray-900">
+                Website Monitor
+              </h1>
+              <p className="text-xs text-gray-500">
+                Sign in to continue
+              </p>
+            </div>
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">
+              Username
+            </label>
+            <input
+              type="text"
+              className="input"
+              autoComplete="username"
+              value={formData.username}
+              onChange={(e) => setFormData({ ...formData, username: e.target.value })}
+              required
+              autoFocus
+            />
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">
+              Password
+            </label>
+            <input
+              type="password"
+              className="input"
+              autoComplete="current-password"
+              value={formData.password}
+              onChange={(e) => setFormData({ ...formData, password: e.target.value })}
+              required
+            />
+          </div>
+
+          <button
+            type="submit"
+            className="btn btn-primary w-full"
+            disabled={submitting}
+          >
+            {submitting ? (
+              <>
+                <RefreshCw className="h-4 w-4 mr-2 animate-spin" />
+                Signing in...
+              </>
+            ) : (
+              <>
+                <LogIn className="h-4 w-4 mr-2" />
+                Sign in
+              </>
+            )}
+          </button>
+        </form>
+      </div>
+    </div>
+  );
+};
+
+export default Login;
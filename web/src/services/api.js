@@ -7,8 +7,34 @@ const API_BASE_URL = process.env.NODE_ENV === 'production'
 const api = axios.create({
   baseURL: API_BASE_URL,
   timeout: 10000,
+  withCredentials: true, // Cookie da sessão de login
 });
 
+// Sessão ausente ou expirada: avisar o App para mostrar o login
+let unauthorizedHandler = null;
+
+export const onUnauthorized = (handler) => {
+  unauthorizedHandler = handler;
+};
+
+api.interceptors.response.use(
+  (response) => response,
+  (error) => {
+    const url = error.config?.url || '';
+    if (error.response?.status === 401 && !url.startsWith('/auth/') && unauthorizedHandler) {
+      unauthorizedHandler();
+    }
+    return Promise.reject(error);
+  }
+);
+
+// Login
+export const authAPI = {
+  login: (username, password) => api.post('/auth/login', { username, password }),
+  logout: () => api.post('/auth/logout'),
+  me: () => api.get('/auth/me'),
+};
+
 // Sites
 export const sitesAPI = {
   getAll: () => api.get('/sites'),